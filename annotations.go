@@ -0,0 +1,197 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kdeconinck/xunit"
+)
+
+// A frame of a .NET stack trace which contains a file and a line (for example, "at NS.Class.Method() in
+// /src/Class.cs:line 12").
+var stackFrame = regexp.MustCompile(`(?m)^\s*at .+ in (.+):line (\d+)\s*$`)
+
+// An annotation is a message about a failing test, which a CI system shows next to the code of the test.
+type annotation struct {
+	file    string // The path of the file, relative to the root of the repository, empty if unknown.
+	line    int    // The line in file, 0 if unknown.
+	title   string // The title of the annotation.
+	message string // The message of the annotation.
+}
+
+// Write the failing tests in sources to w as GitHub Actions workflow commands, which annotate the code of the tests.
+func writeGitHub(w io.Writer, cfg configuration, sources []source) error {
+	for _, a := range annotations(cfg, sources) {
+		props := []string{}
+
+		if a.file != "" {
+			props = append(props, "file="+escapeGitHubProperty(a.file))
+		}
+
+		if a.line > 0 {
+			props = append(props, fmt.Sprintf("line=%v", a.line))
+		}
+
+		props = append(props, "title="+escapeGitHubProperty(a.title))
+
+		if _, err := fmt.Fprintf(w, "::error %s::%s\n", strings.Join(props, ","), escapeGitHubData(a.message)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Write the failing tests in sources to w as Azure Pipelines logging commands, which annotate the code of the tests.
+func writeAzure(w io.Writer, cfg configuration, sources []source) error {
+	for _, a := range annotations(cfg, sources) {
+		props := "type=error;"
+
+		if a.file != "" {
+			props += "sourcepath=" + escapeAzureProperty(a.file) + ";"
+		}
+
+		if a.line > 0 {
+			props += fmt.Sprintf("linenumber=%v;", a.line)
+		}
+
+		msg := escapeAzureData(a.title + ": " + a.message)
+
+		if _, err := fmt.Fprintf(w, "##vso[task.logissue %s]%s\n", props, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns an annotation for each failing test in sources, in the order the tests are rendered.
+func annotations(cfg configuration, sources []source) []annotation {
+	res := make([]annotation, 0)
+
+	for _, src := range sources {
+		for _, assembly := range src.run.Assemblies {
+			for _, tc := range assembly.Tests() {
+				if tc.Result != xunit.Fail {
+					continue
+				}
+
+				file, line := location(tc)
+
+				res = append(res, annotation{
+					file:    cfg.rewritePath(file),
+					line:    line,
+					title:   assembly.Name + ": " + strings.Join(append(append([]string{}, tc.Groups...), tc.Name), " › "),
+					message: failureMessage(tc),
+				})
+			}
+		}
+	}
+
+	return res
+}
+
+// Returns the file and the line which caused tc (a failing test) to fail.
+// The first frame of the stack trace which contains a file is preferred, since it points to the failing line. When
+// there's no such frame, the location of the test itself is returned.
+func location(tc xunit.TestCase) (string, int) {
+	if tc.Failure != nil {
+		if m := stackFrame.FindStringSubmatch(tc.Failure.StackTrace); m != nil {
+			line, _ := strconv.Atoi(m[2])
+
+			return strings.TrimSpace(m[1]), line
+		}
+	}
+
+	return tc.SourceFile, tc.SourceLine
+}
+
+// Returns the message of the failure of tc (a failing test).
+func failureMessage(tc xunit.TestCase) string {
+	if tc.Failure == nil {
+		return "The test failed."
+	}
+
+	msg := strings.TrimSpace(tc.Failure.Message)
+
+	if tc.Failure.ExceptionType != "" {
+		msg = strings.TrimSpace(tc.Failure.ExceptionType + ": " + msg)
+	}
+
+	if msg == "" {
+		return "The test failed."
+	}
+
+	return msg
+}
+
+// Returns path, relative to the root of the repository, using the path prefixes of cfg.
+// Paths are compared with forward slashes, and the longest matching prefix is replaced. When no prefix matches, the
+// path is returned (with forward slashes) as is.
+func (cfg configuration) rewritePath(path string) string {
+	path = strings.ReplaceAll(path, "\\", "/")
+	prefixes := make([]string, 0, len(cfg.Output.PathPrefixes))
+
+	for from := range cfg.Output.PathPrefixes {
+		prefixes = append(prefixes, from)
+	}
+
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	for _, from := range prefixes {
+		if rest, ok := strings.CutPrefix(path, strings.ReplaceAll(from, "\\", "/")); ok {
+			return cfg.Output.PathPrefixes[from] + rest
+		}
+	}
+
+	return path
+}
+
+// Returns v, escaped for use as the data of a GitHub Actions workflow command.
+func escapeGitHubData(v string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(v)
+}
+
+// Returns v, escaped for use as the value of a property of a GitHub Actions workflow command.
+func escapeGitHubProperty(v string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(v)
+}
+
+// Returns v, escaped for use as the message of an Azure Pipelines logging command.
+func escapeAzureData(v string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A").Replace(v)
+}
+
+// Returns v, escaped for use as the value of a property of an Azure Pipelines logging command.
+func escapeAzureProperty(v string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", ";", "%3B", "]", "%5D").Replace(v)
+}
@@ -28,202 +28,148 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+)
+
+// The name of the application, as it's used on the command line.
+const appName = "dotnet-test-visualizer"
 
-	"github.com/kdeconinck/camelcase"
-	"github.com/kdeconinck/words"
-	"github.com/kdeconinck/xunit"
+// The exit codes of the application.
+const (
+	exitOK       = 0 // Everything went fine, and none of the tests failed.
+	exitFailures = 1 // At least one test failed (what's considered a failure is controlled by `--fail-on`).
+	exitUsage    = 2 // The application was invoked with invalid arguments or an invalid configuration.
+	exitInput    = 3 // At least one input file couldn't be read or parsed.
+	exitOutput   = 4 // The output couldn't be written.
 )
 
-// The configuration for the application.
-type configuration struct {
-	ThresholdFast   float32 `json:"thresholdFast"`
-	ThresholdNormal float32 `json:"thresholdNormal"`
+// The commands that are supported by the application.
+// The first command is the one that's executed when no command is specified on the command line.
+var commands = []command{
+	{name: "render", summary: "Print the result(s) of each test, grouped by trait and nested class.", run: runRender},
+	{name: "summary", summary: "Print a summary of each assembly, without the individual test(s).", run: runSummary},
+	{name: "stats", summary: "Print statistics about the result(s) and the duration of the test(s).", run: runStats},
+	{name: "diff", summary: "Print the differences between a baseline and a current test run.", run: runDiff},
+	{name: "merge", summary: "Merge the test run(s) into a single test run, written as xUnit's v2 XML.", run: runMerge},
+	{name: "config", summary: "Print the effective configuration, as JSON.", run: runConfig},
 }
 
-// The standard configuration for the application.
-var stdConfiguration configuration = configuration{
-	ThresholdFast:   0.05,
-	ThresholdNormal: 0.1,
+// An app contains the environment the application interacts with.
+// All the output of the application is written to these streams, which makes each command testable.
+type app struct {
+	workDir string                  // The working directory, used to discover the configuration file.
+	stdin   io.Reader               // The stream to read input from.
+	stdout  io.Writer               // The stream to write regular output to.
+	stderr  io.Writer               // The stream to write diagnostic output to.
+	getenv  func(key string) string // Returns the value of the environment variable named key.
+	colors  *colors                 // The colors of the diagnostic output, <nil> for the standard colors.
 }
 
-// FindNamed returns the value(s) of a "named" argument if it's found.
-// It returns a NON <nil> error if either the "named" argument hasn't been found, or when any of the "named" arguments
-// doesn't have a value.
-func FindNamed(key string) ([]string, error) {
-	var args = os.Args[1:]
-	var result = make([]string, 0)
-
-	for idx, arg := range args {
-		if arg == key && idx >= len(args)-1 {
-			return result, fmt.Errorf("no value found for arg '%s'", key)
-		}
+// The main entry point for the application.
+func main() {
+	workDir, _ := os.Getwd()
+	a := &app{workDir: workDir, stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
 
-		if arg == key && len(args) >= idx {
-			valuePos := idx + 1
+	os.Exit(a.run(os.Args[1:]))
+}
 
-			result = append(result, args[valuePos])
-		}
-	}
+// Run the command specified in args and return the exit code of the application.
+// When args doesn't start with a command, the first command is executed, which keeps the original
+// `--logFile <file>` invocation working.
+func (a *app) run(args []string) int {
+	if len(args) == 0 {
+		a.printUsage(a.stderr)
 
-	if len(result) == 0 {
-		return result, fmt.Errorf("arg '%s' not found", key)
+		return exitUsage
 	}
 
-	return result, nil
-}
+	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		a.printUsage(a.stdout)
 
-// The main entry point for the application.
-func main() {
-	// Configuration of the application.
-	camelcase.NoSplit = []string{"HostBuilder", "DBSyncer", "DbSynchronizer"}
-	words.NoTransform = []string{"DbSynchronizer", "DBSyncer"}
-
-	// Prints the ASCII header.
-	fmt.Println("    _  _ ___ _____   _____       _    __   ___              _ _            ")
-	fmt.Println("   | \\| | __|_   _| |_   _|__ __| |_  \\ \\ / (_)____  _ __ _| (_)______ _ _ ")
-	fmt.Println("  _| .` | _|  | |     | |/ -_|_-<  _|  \\ V /| (_-< || / _` | | |_ / -_) '_|")
-	fmt.Println(" (_)_|\\_|___| |_|     |_|\\___/__/\\__|   \\_/ |_/__/\\_,_\\__,_|_|_/__\\___|_|  ")
-	fmt.Println("")
-
-	// Parse the arguments that are passed to the application.
-	lFiles, err := FindNamed("--logFile")
-
-	// If there aren't any LOG files found to process, terminate the application with a failure message.
-	if err != nil {
-		fmt.Println("\033[1;31mFailed\033[0m: No LOG files found to process.")
-		fmt.Println("        Use the `--logFile` argument to pass a file containing logs in xUnit's v2+ XML format.")
-		fmt.Println("        If you want to specify multiple files, pass the argument once for each log file.")
-		fmt.Println("")
-
-		os.Exit(0)
+		return exitOK
 	}
 
-	// Loop over all the LOG files containing results and parse them.
-	for _, logFile := range lFiles {
-		rdr, err := os.Open(logFile)
-
-		if err != nil {
-			fmt.Printf("\033[1;31mFailed\033[0m - %s\n", err.Error())
-		}
+	cmd := &commands[0]
 
-		tRun, err := xunit.Load(rdr)
+	if !strings.HasPrefix(args[0], "-") {
+		if cmd = findCommand(args[0]); cmd == nil {
+			fmt.Fprintf(a.stderr, "%s: Unknown command '%s'.\n\n", a.failed(), args[0])
+			a.printUsage(a.stderr)
 
-		if err != nil {
-			fmt.Printf("\033[1;31mFailed\033[0m - %s\n", err.Error())
+			return exitUsage
 		}
 
-		fmt.Printf("Input source:         %s\r\n", logFile)
-		fmt.Printf("Amount of assemblies: %v\r\n", len(tRun.Assemblies))
+		args = args[1:]
+	}
 
-		if tRun.Computer != "" {
-			fmt.Printf("Computer:             %s\r\n", tRun.Computer)
-		}
+	return cmd.run(a, cmd, args)
+}
 
-		if tRun.User != "" {
-			fmt.Printf("User:                 %s\r\n", tRun.User)
-		}
+// Returns the prefix of the error messages that are written to the stderr stream of a.
+// The prefix is painted with the color of failures, unless colors are disabled.
+func (a *app) failed() string {
+	c := stdConfiguration.Colors
 
-		if tRun.StartTimeRTF != "" {
-			fmt.Printf("Start time:           %s\r\n", tRun.StartTimeRTF)
-		}
+	if a.colors != nil {
+		c = *a.colors
+	}
 
-		if tRun.EndTimeRTF != "" {
-			fmt.Printf("End time:             %s\r\n", tRun.EndTimeRTF)
-		} else if tRun.Timestamp != "" {
-			fmt.Printf("End time:             %s\r\n", tRun.Timestamp)
-		}
+	if !c.Enabled || c.Fail == "" {
+		return "Failed"
+	}
 
-		// Loop over the assemblies and print the results accordingly.
-		for _, assembly := range tRun.Assemblies {
-			fmt.Println("")
-			fmt.Printf("  Assembly:         %s", assembly.Name)
+	return "\033[" + c.Fail + "mFailed\033[0m"
+}
 
-			if assembly.FailedCount != 0 {
-				fmt.Printf(" - \033[1;31m⛌ Failed (%v of %v failed).\033[0m\r\n", assembly.FailedCount, assembly.TotalCount)
-			} else {
-				fmt.Printf(" - \033[1;32m✓ Passed (%v of %v passed).\033[0m \r\n", assembly.PassedCount, assembly.TotalCount)
-			}
+// Print the usage of the application to w.
+func (a *app) printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [file ...]\n\n", appName)
+	fmt.Fprintln(w, "Commands:")
 
-			fmt.Printf("  Date / time:      %s %s\r\n", assembly.RunDate, assembly.RunTime)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
 
-			if assembly.TimeRTF != "" {
-				fmt.Printf("  Total time:       %v.\r\n", assembly.TimeRTF)
-			} else {
-				fmt.Printf("  Total time:       %v seconds.\r\n", assembly.Time)
-			}
+	fmt.Fprintf(w, "\nRun '%s <command> --help' for more information about a command.\n", appName)
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Exit codes:")
+	fmt.Fprintf(w, "  %v  All the tests passed.\n", exitOK)
+	fmt.Fprintf(w, "  %v  At least one test failed (see `--fail-on`).\n", exitFailures)
+	fmt.Fprintf(w, "  %v  Invalid arguments or configuration.\n", exitUsage)
+	fmt.Fprintf(w, "  %v  At least one input file couldn't be read or parsed.\n", exitInput)
+	fmt.Fprintf(w, "  %v  The output couldn't be written.\n", exitOutput)
+}
 
-			// Print information about the assembly.
-			fmt.Println("")
-			fmt.Printf("  # tests:        %v\r\n", assembly.TotalCount)
-			fmt.Printf("  # Passed tests: %v\r\n", assembly.PassedCount)
-			fmt.Printf("  # Failed tests: %v\r\n", assembly.FailedCount)
-			fmt.Printf("  # Errors:       %v\r\n", assembly.ErrorCount)
-			fmt.Println("")
-
-			// Loop over all the groups in the assembly.
-			for _, tGroup := range assembly.TestGroups {
-				if tGroup.Name != "" {
-					fmt.Println("")
-					fmt.Printf("  Trait: %s\r\n", tGroup.Name)
-				}
-
-				// Loop over all the test(s) in this group.
-				for _, tc := range tGroup.Tests {
-					status := "\033[1;32m✓\033[0m"
-					if tc.Result != "Pass" {
-						status = "\033[1;31m⛌\033[0m"
-					}
-
-					suffix := ""
-
-					if tGroup.Name != "" {
-						suffix = "  "
-					}
-
-					if tc.Time <= stdConfiguration.ThresholdFast {
-						fmt.Printf("%s  🚀 %s %s (%v seconds)\r\n", suffix, status, tc.Name, tc.Time)
-					} else if tc.Time <= stdConfiguration.ThresholdNormal {
-						fmt.Printf("%s  🕐 %s %s (%v seconds)\r\n", suffix, status, tc.Name, tc.Time)
-					} else {
-						fmt.Printf("%s  🐌 %s %s (%v seconds)\r\n", suffix, status, tc.Name, tc.Time)
-					}
-				}
-
-				// Loop over all the groups in this group.
-				for _, group := range tGroup.Groups {
-					fmt.Println("")
-					PrintGroup(group, "")
-				}
+// Returns the exit code of the application after processing sources.
+// When loadFailed is true, at least one of the input files couldn't be loaded, which takes precedence over failing
+// tests. What's considered a failing test is controlled by the `failOn` setting of cfg.
+func exitCode(cfg configuration, sources []source, loadFailed bool) int {
+	if loadFailed {
+		return exitInput
+	}
+
+	for _, src := range sources {
+		for _, assembly := range src.run.Assemblies {
+			if (cfg.failsOn(failOnFailures) && assembly.FailedCount > 0) ||
+				(cfg.failsOn(failOnErrors) && assembly.ErrorCount > 0) ||
+				(cfg.failsOn(failOnSkips) && assembly.SkippedCount > 0) {
+				return exitFailures
 			}
 		}
 	}
-}
 
-func PrintGroup(group *xunit.TestGroup, indent string) {
-	fmt.Printf("%s  %s\r\n", indent, group.Name)
-
-	// Loop over all the test(s) in this group.
-	for _, tc := range group.Tests {
-		status := "\033[1;32m✓\033[0m"
-		if tc.Result != "Pass" {
-			status = "\033[1;31m⛌\033[0m"
-		}
+	return exitOK
+}
 
-		if tc.Time <= stdConfiguration.ThresholdFast {
-			fmt.Printf("%s     🚀 %s %s (%v seconds)\r\n", indent, status, tc.Name, tc.Time)
-		} else if tc.Time <= stdConfiguration.ThresholdNormal {
-			fmt.Printf("%s     🕐 %s %s (%v seconds)\r\n", indent, status, tc.Name, tc.Time)
-		} else {
-			fmt.Printf("%s     🐌 %s %s (%v seconds)\r\n", indent, status, tc.Name, tc.Time)
+// Returns the command named name, or <nil> if there's no such command.
+func findCommand(name string) *command {
+	for idx := range commands {
+		if commands[idx].name == name {
+			return &commands[idx]
 		}
 	}
 
-	if len(group.Tests) > 0 {
-		fmt.Println("")
-	}
-
-	for _, group = range group.Groups {
-		PrintGroup(group, indent+"  ")
-	}
+	return nil
 }
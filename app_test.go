@@ -0,0 +1,1267 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify the command-line interface of the application.
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/assert"
+)
+
+// The content of a file containing a .NET test result in xUnit's v2+ XML format.
+const xmlData = "<assemblies computer=\"WIN11\" user=\"Kevin\">\n" +
+	"  <assembly name=\"C:\\Parent\\Sub\\App.dll\" failed=\"1\" passed=\"1\" skipped=\"1\" total=\"3\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"NS.TestClass+Method.ReturnsTrue\" result=\"Pass\" time=\"0.01\" />\n" +
+	"      <test name=\"NS.TestClass.ThrowsAnException\" result=\"Fail\" time=\"0.5\">\n" +
+	"        <failure exception-type=\"System.InvalidOperationException\">\n" +
+	"          <message>Operation is not valid.</message>\n" +
+	"        </failure>\n" +
+	"      </test>\n" +
+	"      <test name=\"NS.TestClass.IsSkipped\" result=\"Skip\">\n" +
+	"        <reason>Not implemented yet.</reason>\n" +
+	"      </test>\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"</assemblies>"
+
+// The content of a file containing a .NET test result in TRX format.
+const trxData = "<TestRun runUser=\"Kevin\" xmlns=\"http://microsoft.com/schemas/VisualStudio/TeamTest/2010\">\n" +
+	"  <Results>\n" +
+	"    <UnitTestResult testId=\"t1\" testName=\"NS.TestClass+Method.ReturnsTrue\" outcome=\"Passed\" />\n" +
+	"  </Results>\n" +
+	"  <TestDefinitions>\n" +
+	"    <UnitTest id=\"t1\"><TestMethod codeBase=\"/src/App.Trx.dll\" className=\"NS.TestClass+Method\" /></UnitTest>\n" +
+	"  </TestDefinitions>\n" +
+	"</TestRun>"
+
+// The content of a file containing a .NET test result in NUnit's v3 XML format.
+const nunitData = "<test-run id=\"0\">\n" +
+	"  <test-suite type=\"Assembly\" name=\"App.NUnit.dll\">\n" +
+	"    <test-suite type=\"TestFixture\" name=\"TestClass\">\n" +
+	"      <test-case name=\"ReturnsTrue\" result=\"Passed\" duration=\"0.01\" />\n" +
+	"    </test-suite>\n" +
+	"  </test-suite>\n" +
+	"</test-run>"
+
+// The content of a file containing a failing test with a location, in xUnit's v2+ XML format.
+const locationData = "<assemblies>\n" +
+	"  <assembly name=\"D:\\a\\1\\s\\bin\\App.dll\" failed=\"1\" total=\"1\">\n" +
+	"    <collection>\n" +
+	"      <test name=\"NS.TestClass.Fails\" result=\"Fail\" source-file=\"D:\\a\\1\\s\\tests\\TestClass.cs\" source-line=\"10\">\n" +
+	"        <failure exception-type=\"System.Exception\">\n" +
+	"          <message>Boom, 100%\nSecond line</message>\n" +
+	"          <stack-trace>   at NS.TestClass.Fails() in D:\\a\\1\\s\\tests\\TestClass.cs:line 14</stack-trace>\n" +
+	"        </failure>\n" +
+	"      </test>\n" +
+	"    </collection>\n" +
+	"  </assembly>\n" +
+	"</assemblies>"
+
+// Returns the path of a temporary file, containing data.
+func writeTempFile(t *testing.T, name, data string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write temporary file: %v", err)
+	}
+
+	return path
+}
+
+// Run the application in workDir with args and return the exit code, and what was written to stdout and stderr.
+func runApp(workDir string, args []string) (int, string, string) {
+	return runAppWithEnv(workDir, nil, args)
+}
+
+// Run the application in workDir with args, in an environment which only contains the variables in env, and return
+// the exit code, and what was written to stdout and stderr.
+func runAppWithEnv(workDir string, env map[string]string, args []string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	a := &app{
+		workDir: workDir,
+		stdin:   strings.NewReader(""),
+		stdout:  &stdout,
+		stderr:  &stderr,
+		getenv:  func(key string) string { return env[key] },
+	}
+
+	code := a.run(args)
+
+	return code, stdout.String(), stderr.String()
+}
+
+// UT: Run the application.
+func TestRun(t *testing.T) {
+	logFile := writeTempFile(t, "result.xml", xmlData)
+	shardFile := writeTempFile(t, "shard.xml", "<assemblies>\n"+
+		"  <assembly name=\"/agent2/App.dll\" total=\"1\" passed=\"1\" time=\"1\">\n"+
+		"    <collection><test name=\"NS.TestClass.RunsOnShard2\" result=\"Pass\" time=\"1\" /></collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+	envFile := writeTempFile(t, "environment.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\" total=\"0\" target-framework=\"net8.0\" test-framework=\"xUnit.net 2.5.0\""+
+		" environment=\"64-bit .NET 8.0.1 [collection-per-class, parallel (16 threads)]\" config-file=\"/src/xunit.runner.json\" />\n"+
+		"</assemblies>")
+	errorFile := writeTempFile(t, "errors.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\" total=\"0\" errors=\"1\">\n"+
+		"    <errors>\n"+
+		"      <error type=\"test-collection-cleanup\" name=\"Test collection for NS.DbTests\">\n"+
+		"        <failure exception-type=\"System.ObjectDisposedException\">\n"+
+		"          <message>Cannot access a disposed object.</message>\n"+
+		"          <stack-trace>   at NS.DbFixture.Dispose()</stack-trace>\n"+
+		"        </failure>\n"+
+		"      </error>\n"+
+		"    </errors>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+
+	for _, tc := range []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:     "Print a summary, with the environment of each assembly.",
+			args:     []string{"summary", "--verbose", envFile},
+			wantCode: 0,
+			wantStdout: []string{
+				"Environment:      net8.0 · xUnit.net 2.5.0 · 64-bit .NET 8.0.1 [collection-per-class, parallel (16 threads)]",
+				"Config file:      /src/xunit.runner.json",
+			},
+		},
+		{
+			name:       "Write the environment of each assembly in JSON.",
+			args:       []string{"render", "--format", "json", envFile},
+			wantCode:   0,
+			wantStdout: []string{`"targetFramework": "net8.0"`, `"testFramework": "xUnit.net 2.5.0"`},
+		},
+		{
+			name:     "Print a summary, with the environmental errors.",
+			args:     []string{"summary", "--color=false", errorFile},
+			wantCode: 1,
+			wantStdout: []string{
+				"# Errors:        1", "Environment errors:",
+				"⛌ test-collection-cleanup: Test collection for NS.DbTests", "System.ObjectDisposedException",
+				"Cannot access a disposed object.", "at NS.DbFixture.Dispose()",
+			},
+		},
+		{
+			name:       "Write the environmental errors in JSON.",
+			args:       []string{"render", "--format", "json", errorFile},
+			wantCode:   1,
+			wantStdout: []string{`"errors": [`, `"type": "test-collection-cleanup"`, `"message": "Cannot access a disposed object."`},
+		},
+		{
+			name:       "Write the environmental errors in HTML.",
+			args:       []string{"render", "--format", "html", errorFile},
+			wantCode:   1,
+			wantStdout: []string{"<h4>Environment errors</h4>", "test-collection-cleanup: Test collection for NS.DbTests"},
+		},
+		{
+			name:       "Print the usage of the application.",
+			args:       []string{"--help"},
+			wantCode:   0,
+			wantStdout: []string{"Usage: dotnet-test-visualizer <command>", "render", "summary", "stats"},
+		},
+		{
+			name:       "Run an unknown command.",
+			args:       []string{"unknown"},
+			wantCode:   2,
+			wantStderr: []string{"Unknown command 'unknown'."},
+		},
+		{
+			name:       "Pass an unknown flag.",
+			args:       []string{"render", "--unknown"},
+			wantCode:   2,
+			wantStderr: []string{"flag provided but not defined: -unknown"},
+		},
+		{
+			name:       "Print the usage of a command.",
+			args:       []string{"stats", "--help"},
+			wantCode:   0,
+			wantStderr: []string{"Usage: dotnet-test-visualizer stats", "-top number"},
+		},
+		{
+			name:       "Render without any input files.",
+			args:       []string{"render"},
+			wantCode:   2,
+			wantStderr: []string{"\033[1;31mFailed\033[0m: No LOG files found to process."},
+		},
+		{
+			name:       "Render a file that doesn't exist, without colors.",
+			args:       []string{"render", "--color=false", filepath.Join(t.TempDir(), "missing.xml")},
+			wantCode:   3,
+			wantStderr: []string{"Failed - open "},
+		},
+		{
+			name:       "Render with a configuration file that doesn't exist, without colors.",
+			args:       []string{"render", "--color=false", "--config", filepath.Join(t.TempDir(), "missing.json"), logFile},
+			wantCode:   2,
+			wantStderr: []string{"Failed: "},
+		},
+		{
+			name:       "Render, without specifying the command.",
+			args:       []string{"--logFile", logFile},
+			wantCode:   1,
+			wantStdout: []string{"Input source:         " + logFile, "Returns true", "Operation is not valid.", "Not implemented yet."},
+		},
+		{
+			name:       "Print a summary, with the input file as a positional argument.",
+			args:       []string{"summary", logFile},
+			wantCode:   1,
+			wantStdout: []string{"Assembly:         App.dll", "# Failed tests:  1"},
+		},
+		{
+			name:       "Print statistics, with flags after the positional arguments.",
+			args:       []string{"stats", logFile, "--top", "1"},
+			wantCode:   1,
+			wantStdout: []string{"Slowest tests:", "Throws an exception (0.5 seconds)"},
+		},
+		{
+			name:       "Print statistics, with a negative number of slowest tests.",
+			args:       []string{"stats", "--top", "-1", logFile},
+			wantCode:   2,
+			wantStderr: []string{"invalid value '-1' for --top, expected a number that isn't negative"},
+		},
+		{
+			name:       "Render without colors and without the ASCII header.",
+			args:       []string{"render", "--color=false", "--header=false", logFile},
+			wantCode:   1,
+			wantStdout: []string{"Input source:", "🚀 ✓ Returns true"},
+		},
+		{
+			name:     "Render without the application failing on failed tests.",
+			args:     []string{"render", "--fail-on", "none", logFile},
+			wantCode: 0,
+		},
+		{
+			name:     "Render, with the application failing on skipped tests only.",
+			args:     []string{"render", "--fail-on", "skips", logFile},
+			wantCode: 1,
+		},
+		{
+			name:       "Render, with an invalid value for `--fail-on`.",
+			args:       []string{"render", "--fail-on", "warnings", logFile},
+			wantCode:   2,
+			wantStderr: []string{"invalid value 'warnings' for failOn"},
+		},
+		{
+			name:       "Render a file that doesn't exist.",
+			args:       []string{"render", "--fail-on", "none", logFile, "unknown.xml"},
+			wantCode:   3,
+			wantStdout: []string{"Input source:         " + logFile},
+			wantStderr: []string{"open unknown.xml"},
+		},
+		{
+			name:       "Render a truncated file, and continue with the other files.",
+			args:       []string{"render", writeTempFile(t, "truncated.xml", xmlData[:100]), logFile},
+			wantCode:   3,
+			wantStdout: []string{"Input source:         " + logFile},
+			wantStderr: []string{"truncated.xml: malformed XML at line 2, column 58: unexpected EOF"},
+		},
+		{
+			name:       "Render a file in TRX format.",
+			args:       []string{"render", writeTempFile(t, "result.trx", trxData)},
+			wantCode:   0,
+			wantStdout: []string{"Assembly:         App.Trx.dll", "Returns true"},
+		},
+		{
+			name:       "Render a file in NUnit's v3 XML format.",
+			args:       []string{"render", writeTempFile(t, "result.xml", nunitData)},
+			wantCode:   0,
+			wantStdout: []string{"Assembly:         App.NUnit.dll", "  Test class", "Returns true"},
+		},
+		{
+			name:       "Render a file in JUnit's XML format.",
+			args:       []string{"render", writeTempFile(t, "result.xml", "<testsuite name=\"App.JUnit\"><testcase name=\"ReturnsTrue\" /></testsuite>")},
+			wantCode:   0,
+			wantStdout: []string{"Assembly:         App.JUnit", "Returns true"},
+		},
+		{
+			name:       "Convert a file into JUnit's XML format.",
+			args:       []string{"render", "--format", "junit", logFile},
+			wantCode:   1,
+			wantStdout: []string{"<testsuites tests=", "<testsuite name=\"App.dll\"", "<skipped message=\"Not implemented yet.\">"},
+		},
+		{
+			name:       "Convert a file into a JSON report.",
+			args:       []string{"render", "--format", "json", logFile},
+			wantCode:   1,
+			wantStdout: []string{"\"schemaVersion\": 1", "\"name\": \"Returns true\"", "\"path\": [\n", "\"result\": \"fail\"", "\"speed\": \"fast\""},
+		},
+		{
+			name:       "Convert a file into an HTML report.",
+			args:       []string{"render", "--format", "html", logFile},
+			wantCode:   1,
+			wantStdout: []string{"<!DOCTYPE html>", "<h3>App.dll", "data-name=\"Returns true\"", "Operation is not valid.", "Reason: Not implemented yet."},
+		},
+		{
+			name:       "Convert a file into a Markdown summary.",
+			args:       []string{"render", "--format", "markdown", logFile},
+			wantCode:   1,
+			wantStdout: []string{"## Test results", "| ⛌ | App.dll | 1 | 1 | 1 | 0 |", "<summary>⛌ App.dll: 1 failing test(s)</summary>", "Operation is not valid."},
+		},
+		{
+			name:       "Convert a file into a Markdown summary which exceeds the limit.",
+			args:       []string{"render", "--format", "markdown", "--markdown-limit", "600", logFile},
+			wantCode:   1,
+			wantStdout: []string{"| ⛌ | App.dll |", "The summary is truncated, since it exceeds 600 bytes. 1 test(s) aren't listed."},
+		},
+		{
+			name:       "Pass a negative Markdown limit.",
+			args:       []string{"render", "--format", "markdown", "--markdown-limit", "-1", logFile},
+			wantCode:   2,
+			wantStderr: []string{"the Markdown limit can't be negative"},
+		},
+		{
+			name:       "Append to GitHub's step summary, outside of GitHub Actions.",
+			args:       []string{"render", "--step-summary", logFile},
+			wantCode:   1,
+			wantStderr: []string{"$GITHUB_STEP_SUMMARY isn't set"},
+		},
+		{
+			name:       "Annotate the failing tests in GitHub Actions, with a rewritten path.",
+			args:       []string{"render", "--format", "github", "--path-prefix", "D:\\a\\1\\s\\=", writeTempFile(t, "result.xml", locationData)},
+			wantCode:   1,
+			wantStdout: []string{"::error file=tests/TestClass.cs,line=14,title=App.dll%3A Fails::System.Exception: Boom, 100%25%0ASecond line\n"},
+		},
+		{
+			name:       "Annotate the failing tests in Azure Pipelines, without a location.",
+			args:       []string{"render", "--format", "azure", logFile},
+			wantCode:   1,
+			wantStdout: []string{"##vso[task.logissue type=error;]App.dll: Throws an exception: System.InvalidOperationException: Operation is not valid.\n"},
+		},
+		{
+			name:       "Pass an invalid path prefix.",
+			args:       []string{"render", "--format", "github", "--path-prefix", "/src", logFile},
+			wantCode:   2,
+			wantStderr: []string{"invalid value '/src' for --path-prefix, expected from=to"},
+		},
+		{
+			name:       "Merge the shards of a test run into xUnit's v2 XML format.",
+			args:       []string{"merge", logFile, shardFile},
+			wantCode:   1,
+			wantStdout: []string{"<assemblies computer=\"WIN11\" user=\"Kevin\">", "<assembly name=\"App.dll\" time=\"1\" total=\"4\" passed=\"2\" failed=\"1\" skipped=\"1\""},
+		},
+		{
+			name:       "Merge the shards of a test run, and render the result.",
+			args:       []string{"merge", "--format", "text", "--fail-on", "none", logFile, shardFile},
+			wantCode:   0,
+			wantStdout: []string{"Input source:         " + logFile + ", " + shardFile, "Amount of assemblies: 1", "Runs on shard2"},
+		},
+		{
+			name:       "Convert a file in TRX format into xUnit's v2 XML format.",
+			args:       []string{"render", "--format", "xunit", writeTempFile(t, "result.trx", trxData)},
+			wantCode:   0,
+			wantStdout: []string{"<test name=\"NS.TestClass+Method.ReturnsTrue\" time=\"0\" result=\"Pass\"></test>"},
+		},
+		{
+			name:       "Pass an invalid output format.",
+			args:       []string{"render", "--format", "pdf", logFile},
+			wantCode:   2,
+			wantStderr: []string{"invalid value 'pdf' for --format, expected one of: azure, github, html, json, junit, markdown, text, xunit"},
+		},
+		{
+			name:       "Write the output to a file that can't be created.",
+			args:       []string{"render", "--out", filepath.Join(t.TempDir(), "missing", "out.xml"), logFile},
+			wantCode:   4,
+			wantStderr: []string{"out.xml"},
+		},
+		{
+			name: "Render a message stream in xUnit's v3 JSON format.",
+			args: []string{"render", writeTempFile(t, "result.json", "\n"+
+				`{"$type":"test-assembly-starting","AssemblyUniqueID":"a1","AssemblyPath":"/src/App.V3.dll"}`+"\n"+
+				`{"$type":"test-starting","AssemblyUniqueID":"a1","TestUniqueID":"t1","TestDisplayName":"NS.TestClass.ReturnsTrue"}`+"\n"+
+				`{"$type":"test-passed","AssemblyUniqueID":"a1","TestUniqueID":"t1","ExecutionTime":0.01}`)},
+			wantCode:   0,
+			wantStdout: []string{"Assembly:         App.V3.dll", "Returns true"},
+		},
+		{
+			name:       "Render a file in an unknown format.",
+			args:       []string{"render", writeTempFile(t, "unknown.xml", "<?xml version=\"1.0\"?><unknown />")},
+			wantCode:   3,
+			wantStderr: []string{"unsupported format, unknown root element <unknown>"},
+		},
+		{
+			name:       "Run without any arguments.",
+			args:       []string{},
+			wantCode:   2,
+			wantStderr: []string{"Usage: dotnet-test-visualizer <command>", "Exit codes:"},
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := runApp("", tc.args)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %v\033[0m\n"+
+			"\033[31mActual:     Exit code %v\033[0m\n\n", tc.name, tc.args, tc.wantCode, code)
+
+		for _, want := range tc.wantStdout {
+			assert.Equal(t, strings.Contains(stdout, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stdout)
+		}
+
+		for _, want := range tc.wantStderr {
+			assert.Equal(t, strings.Contains(stderr, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stderr)
+		}
+	}
+}
+
+// UT: Append a Markdown summary to GitHub's step summary.
+func TestRun_StepSummary(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	logFile := writeTempFile(t, "result.xml", xmlData)
+	summary := writeTempFile(t, "summary.md", "# Previous step\n")
+
+	// ACT.
+	code, _, _ := runAppWithEnv("", map[string]string{"GITHUB_STEP_SUMMARY": summary}, []string{"render", "--step-summary", logFile})
+	data, err := os.ReadFile(summary)
+
+	// ASSERT.
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Append a Markdown summary to GitHub's step summary.\n"+
+		"\033[32mExpected:   No error\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", err)
+
+	assert.Equal(t, code, 1, "", "\n\n"+
+		"UT Name:    Append a Markdown summary to GitHub's step summary.\n"+
+		"\033[32mExpected:   Exit code 1\033[0m\n"+
+		"\033[31mActual:     Exit code %v\033[0m\n\n", code)
+
+	assert.Equal(t, strings.HasPrefix(string(data), "# Previous step\n## Test results\n"), true, "", "\n\n"+
+		"UT Name:    Append a Markdown summary to GitHub's step summary.\n"+
+		"\033[32mExpected:   The summary appended to the existing one\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", data)
+}
+
+// UT: Narrow the test(s) that are written, using filters.
+func TestRun_Filter(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	logFile := writeTempFile(t, "result.xml", xmlData)
+	trxFile := writeTempFile(t, "result.trx", trxData)
+
+	for _, tc := range []struct {
+		name        string
+		args        []string
+		wantCode    int
+		wantStdout  []string
+		wantMissing []string
+		wantStderr  []string
+	}{
+		{
+			name:        "Render the failed and skipped tests only.",
+			args:        []string{"render", "--only", "failed,skipped", logFile},
+			wantCode:    1,
+			wantStdout:  []string{"Throws an exception", "Is skipped", "⛌ Failed (1 of 2 failed)."},
+			wantMissing: []string{"Returns true"},
+		},
+		{
+			name:        "Render the passed tests only, which doesn't change the exit code.",
+			args:        []string{"render", "--only", "passed", logFile},
+			wantCode:    1,
+			wantStdout:  []string{"Returns true", "✓ Passed (1 of 1 passed)."},
+			wantMissing: []string{"Throws an exception", "Is skipped"},
+		},
+		{
+			name:        "Summarize a single assembly.",
+			args:        []string{"summary", "--fail-on", "none", "--assembly", "app.trx.dll", logFile, trxFile},
+			wantCode:    0,
+			wantStdout:  []string{"Assembly:         App.Trx.dll", "Amount of assemblies: 0"},
+			wantMissing: []string{"Assembly:         App.dll"},
+		},
+		{
+			name:        "Render the tests matching a name, excluding others.",
+			args:        []string{"render", "--name", "^NS\\.TestClass\\.", "--exclude-name", "Skipped$", logFile},
+			wantCode:    1,
+			wantStdout:  []string{"Throws an exception"},
+			wantMissing: []string{"Returns true", "Is skipped"},
+		},
+		{
+			name:        "Render the tests with a trait, which none of the tests have.",
+			args:        []string{"render", "--trait", "Category=Integration", logFile},
+			wantCode:    1,
+			wantMissing: []string{"Assembly:", "Returns true"},
+		},
+		{
+			name:       "Pass an invalid result.",
+			args:       []string{"render", "--only", "broken", logFile},
+			wantCode:   2,
+			wantStderr: []string{"invalid value 'broken' for --only, expected one of: failed, notrun, passed, skipped"},
+		},
+		{
+			name:       "Pass an invalid trait.",
+			args:       []string{"render", "--trait", "Category", logFile},
+			wantCode:   2,
+			wantStderr: []string{"invalid value 'Category' for --trait, expected name=value"},
+		},
+		{
+			name:       "Pass an invalid regular expression.",
+			args:       []string{"summary", "--name", "(", logFile},
+			wantCode:   2,
+			wantStderr: []string{"invalid value '(' for --name"},
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := runApp("", tc.args)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %v\033[0m\n"+
+			"\033[31mActual:     Exit code %v\033[0m\n\n", tc.name, tc.args, tc.wantCode, code)
+
+		for _, want := range tc.wantStdout {
+			assert.Equal(t, strings.Contains(stdout, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stdout)
+		}
+
+		for _, missing := range tc.wantMissing {
+			assert.Equal(t, strings.Contains(stdout, missing), false, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout NOT containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, missing, stdout)
+		}
+
+		for _, want := range tc.wantStderr {
+			assert.Equal(t, strings.Contains(stderr, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stderr)
+		}
+	}
+}
+
+// UT: Print the output and the warnings of the test(s).
+func TestRun_Output(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	logFile := writeTempFile(t, "result.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\" total=\"2\" passed=\"1\" failed=\"1\">\n"+
+		"    <collection>\n"+
+		"      <test name=\"NS.TestClass.Passes\" result=\"Pass\">\n"+
+		"        <output>Connected to the database.</output>\n"+
+		"        <warnings><warning>The API is deprecated.</warning></warnings>\n"+
+		"      </test>\n"+
+		"      <test name=\"NS.TestClass.Fails\" result=\"Fail\">\n"+
+		"        <failure><message>Boom.</message></failure>\n"+
+		"        <output>Line 1\nLine 2\nLine 3\n</output>\n"+
+		"      </test>\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+
+	for _, tc := range []struct {
+		name        string
+		args        []string
+		wantCode    int
+		wantStdout  []string
+		wantMissing []string
+		wantStderr  []string
+	}{
+		{
+			name:        "Render the output of the failed tests.",
+			args:        []string{"render", "--color=false", logFile},
+			wantCode:    1,
+			wantStdout:  []string{"Warning: The API is deprecated.", "Output:", "  Line 1\r\n", "  Line 3\r\n"},
+			wantMissing: []string{"Connected to the database.", "more line(s)"},
+		},
+		{
+			name:       "Render the output of all the tests.",
+			args:       []string{"render", "--show-output", "all", logFile},
+			wantCode:   1,
+			wantStdout: []string{"Connected to the database.", "Line 3"},
+		},
+		{
+			name:        "Render the output, truncated to a number of lines.",
+			args:        []string{"render", "--color=false", "--output-lines", "1", logFile},
+			wantCode:    1,
+			wantStdout:  []string{"Line 1", "... 2 more line(s), the full output is available in the JSON and HTML reports."},
+			wantMissing: []string{"Line 2"},
+		},
+		{
+			name:        "Render without any output.",
+			args:        []string{"render", "--show-output", "never", logFile},
+			wantCode:    1,
+			wantStdout:  []string{"The API is deprecated."},
+			wantMissing: []string{"Output:", "Line 1"},
+		},
+		{
+			name:       "Write the full output in JSON.",
+			args:       []string{"render", "--format", "json", "--output-lines", "1", logFile},
+			wantCode:   1,
+			wantStdout: []string{`"output": "Line 1\nLine 2\nLine 3\n"`, `"warnings": [`},
+		},
+		{
+			name:       "Pass an invalid value for the output.",
+			args:       []string{"render", "--show-output", "sometimes", logFile},
+			wantCode:   2,
+			wantStderr: []string{"invalid value 'sometimes' for showOutput, expected one of: failed, all, never"},
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := runApp("", tc.args)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %v\033[0m\n"+
+			"\033[31mActual:     Exit code %v\033[0m\n\n", tc.name, tc.args, tc.wantCode, code)
+
+		for _, want := range tc.wantStdout {
+			assert.Equal(t, strings.Contains(stdout, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stdout)
+		}
+
+		for _, missing := range tc.wantMissing {
+			assert.Equal(t, strings.Contains(stdout, missing), false, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout NOT containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, missing, stdout)
+		}
+
+		for _, want := range tc.wantStderr {
+			assert.Equal(t, strings.Contains(stderr, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stderr)
+		}
+	}
+}
+
+// UT: Print the test(s), grouped by the test collection they ran in.
+func TestRun_GroupBy(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	logFile := writeTempFile(t, "result.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\" total=\"3\" passed=\"2\" failed=\"1\" time=\"2\" time-rtf=\"00:00:02\">\n"+
+		"    <collection name=\"Test collection for NS.SlowTests\" total=\"2\" passed=\"1\" failed=\"1\" time=\"1.8\">\n"+
+		"      <test name=\"NS.SlowTests.Connects\" result=\"Pass\" time=\"0.8\">\n"+
+		"        <traits><trait name=\"Category\" value=\"Database\" /></traits>\n"+
+		"      </test>\n"+
+		"      <test name=\"NS.SlowTests.Fails\" result=\"Fail\" time=\"1\" />\n"+
+		"    </collection>\n"+
+		"    <collection name=\"Test collection for NS.FastTests\" total=\"1\" passed=\"1\" time=\"0.2\">\n"+
+		"      <test name=\"NS.FastTests.Passes\" result=\"Pass\" time=\"0.2\" />\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+
+	for _, tc := range []struct {
+		name        string
+		args        []string
+		wantCode    int
+		wantStdout  []string
+		wantMissing []string
+		wantStderr  []string
+	}{
+		{
+			name:        "Render the tests grouped by trait.",
+			args:        []string{"render", "--color=false", logFile},
+			wantCode:    1,
+			wantStdout:  []string{"Trait: Category - Database"},
+			wantMissing: []string{"Collection:"},
+		},
+		{
+			name:     "Render the tests grouped by collection.",
+			args:     []string{"render", "--color=false", "--group-by", "collection", logFile},
+			wantCode: 1,
+			wantStdout: []string{
+				"Collection: Test collection for NS.SlowTests - 2 test(s), 1.8 seconds (90% of the assembly).",
+				"Collection: Test collection for NS.FastTests - 1 test(s), 0.2 seconds (10% of the assembly).",
+			},
+			wantMissing: []string{"Trait:"},
+		},
+		{
+			name:        "Render the tests grouped by collection, after filtering them.",
+			args:        []string{"render", "--color=false", "--group-by", "collection", "--only", "failed", logFile},
+			wantCode:    1,
+			wantStdout:  []string{"Collection: Test collection for NS.SlowTests - 1 test(s), 1 seconds"},
+			wantMissing: []string{"NS.FastTests"},
+		},
+		{
+			name:       "Pass an invalid value for the grouping.",
+			args:       []string{"render", "--group-by", "class", logFile},
+			wantCode:   2,
+			wantStderr: []string{"invalid value 'class' for groupBy, expected one of: trait, collection"},
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := runApp("", tc.args)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %v\033[0m\n"+
+			"\033[31mActual:     Exit code %v\033[0m\n\n", tc.name, tc.args, tc.wantCode, code)
+
+		for _, want := range tc.wantStdout {
+			assert.Equal(t, strings.Contains(stdout, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stdout)
+		}
+
+		for _, missing := range tc.wantMissing {
+			assert.Equal(t, strings.Contains(stdout, missing), false, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout NOT containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, missing, stdout)
+		}
+
+		for _, want := range tc.wantStderr {
+			assert.Equal(t, strings.Contains(stderr, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stderr)
+		}
+	}
+}
+
+// UT: Print the differences between a baseline and a current test run.
+func TestDiff(t *testing.T) {
+	// ARRANGE.
+	baseline := writeTempFile(t, "baseline.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\">\n"+
+		"    <collection>\n"+
+		"      <test name=\"NS.TestClass.Breaks\" result=\"Pass\" time=\"0.01\" />\n"+
+		"      <test name=\"NS.TestClass.IsFixed\" result=\"Fail\" time=\"0.01\" />\n"+
+		"      <test name=\"NS.TestClass.IsRemoved\" result=\"Pass\" time=\"0.01\" />\n"+
+		"      <test name=\"NS.TestClass.SlowsDown\" result=\"Pass\" time=\"0.1\" />\n"+
+		"      <test name=\"NS.TestClass.Unchanged\" result=\"Pass\" time=\"0.01\" />\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+
+	current := writeTempFile(t, "current.xml", "<assemblies>\n"+
+		"  <assembly name=\"App.dll\">\n"+
+		"    <collection>\n"+
+		"      <test name=\"NS.TestClass.Breaks\" result=\"Fail\" time=\"0.01\">\n"+
+		"        <failure exception-type=\"System.Exception\"><message>Boom.</message></failure>\n"+
+		"      </test>\n"+
+		"      <test name=\"NS.TestClass.IsFixed\" result=\"Pass\" time=\"0.01\" />\n"+
+		"      <test name=\"NS.TestClass.IsAdded\" result=\"Pass\" time=\"0.01\" />\n"+
+		"      <test name=\"NS.TestClass.SlowsDown\" result=\"Pass\" time=\"0.5\" />\n"+
+		"      <test name=\"NS.TestClass.Unchanged\" result=\"Pass\" time=\"0.02\" />\n"+
+		"    </collection>\n"+
+		"  </assembly>\n"+
+		"</assemblies>")
+
+	var zipData bytes.Buffer
+
+	zw := zip.NewWriter(&zipData)
+
+	for _, file := range []string{baseline, current} {
+		if data, err := os.ReadFile(file); err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		} else if f, err := zw.Create(filepath.Base(file)); err != nil {
+			t.Fatalf("failed to create archive: %v", err)
+		} else if _, err := f.Write(data); err != nil {
+			t.Fatalf("failed to create archive: %v", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+
+	archive := writeTempFile(t, "runs.zip", zipData.String())
+
+	for _, tc := range []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:     "Print the differences as a tree.",
+			args:     []string{"--color=false", "--header=false", baseline, current},
+			wantCode: 1,
+			wantStdout: []string{
+				"Newly failing (1):\r\n\n    App.dll\r\n      🚀 ⛌ Breaks (0.01 → 0.01 seconds)\r\n           System.Exception\r\n           Boom.",
+				"Newly passing (1):\r\n\n    App.dll\r\n      🚀 ✓ Is fixed",
+				"Added (1):\r\n\n    App.dll\r\n      🚀 ✓ Is added (0.01 seconds)",
+				"Removed (1):\r\n\n    App.dll\r\n      🚀 ✓ Is removed (0.01 seconds)",
+				"Slower (1):\r\n\n    App.dll\r\n      🐌 ✓ Slows down (0.1 → 0.5 seconds)",
+			},
+		},
+		{
+			name:       "Print the differences of identical test runs.",
+			args:       []string{"--header=false", baseline, baseline},
+			wantCode:   0,
+			wantStdout: []string{"No differences."},
+		},
+		{
+			name:       "Print the differences as JSON.",
+			args:       []string{"--format", "json", baseline, current},
+			wantCode:   1,
+			wantStdout: []string{"\"newlyFailing\": [\n    {\n      \"assembly\": \"App.dll\",\n      \"fullName\": \"NS.TestClass.Breaks\"", "\"faster\": []"},
+		},
+		{
+			name:       "Print the differences as Markdown, without failing on failed tests.",
+			args:       []string{"--format", "markdown", "--fail-on", "none", baseline, current},
+			wantCode:   0,
+			wantStdout: []string{"| Newly failing | 1 |", "### Removed\n\n- **App.dll › Is removed** (0.01 seconds)"},
+		},
+		{
+			name:       "Print the differences, with a stricter definition of slower tests.",
+			args:       []string{"--header=false", "--ratio", "10", baseline, current},
+			wantCode:   1,
+			wantStdout: []string{"Removed (1):"},
+		},
+		{
+			name:       "Print the differences without a current test run.",
+			args:       []string{baseline},
+			wantCode:   2,
+			wantStderr: []string{"expected a baseline and a current file containing test result(s)"},
+		},
+		{
+			name:       "Print the differences in an invalid format.",
+			args:       []string{"--format", "html", baseline, current},
+			wantCode:   2,
+			wantStderr: []string{"invalid value 'html' for --format, expected one of: json, markdown, text"},
+		},
+		{
+			name:       "Print the differences with a baseline that doesn't exist.",
+			args:       []string{"unknown.xml", current},
+			wantCode:   3,
+			wantStderr: []string{"open unknown.xml"},
+		},
+		{
+			name:       "Print the differences with a baseline that contains multiple test runs.",
+			args:       []string{archive, current},
+			wantCode:   2,
+			wantStderr: []string{"expected a single test run in the baseline '" + archive + "', found 2"},
+		},
+		{
+			name:       "Print the differences with a current test run that contains multiple test runs.",
+			args:       []string{baseline, archive},
+			wantCode:   2,
+			wantStderr: []string{"expected a single test run in the current test run '" + archive + "', found 2"},
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := runApp("", append([]string{"diff"}, tc.args...))
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %v\033[0m\n"+
+			"\033[31mActual:     Exit code %v\033[0m\n\n", tc.name, tc.args, tc.wantCode, code)
+
+		for _, want := range tc.wantStdout {
+			assert.Equal(t, strings.Contains(stdout, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stdout)
+		}
+
+		for _, want := range tc.wantStderr {
+			assert.Equal(t, strings.Contains(stderr, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stderr)
+		}
+	}
+}
+
+// UT: Discover the input files, based on directories, glob patterns and stdin.
+func TestRun_Discovery(t *testing.T) {
+	// ARRANGE.
+	dir := t.TempDir()
+
+	for name, data := range map[string]string{
+		filepath.Join("App.Tests", "TestResults", "result.xml"):  xmlData,
+		filepath.Join("App.Trx", "TestResults", "result.trx"):    trxData,
+		filepath.Join("App.Trx", "TestResults", "notes.txt"):     "Not a test result.",
+		filepath.Join("App.NUnit", "TestResults", "nunit.xml"):   nunitData,
+		filepath.Join("App.NUnit", "bin", "App.NUnit.deps.json"): "{}",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o700); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	resultFile := filepath.Join(dir, "App.Tests", "TestResults", "result.xml")
+	artifacts := t.TempDir()
+
+	var gzipData, zipData bytes.Buffer
+
+	zw := zip.NewWriter(&zipData)
+	gw := gzip.NewWriter(&gzipData)
+
+	entries := []string{"App.Tests/result.xml", xmlData, "App.Trx/result.trx", trxData, "readme.md", "# Readme"}
+
+	for idx := 0; idx < len(entries); idx += 2 {
+		if f, err := zw.Create(entries[idx]); err != nil {
+			t.Fatalf("failed to create archive: %v", err)
+		} else if _, err := f.Write([]byte(entries[idx+1])); err != nil {
+			t.Fatalf("failed to create archive: %v", err)
+		}
+	}
+
+	if _, err := gw.Write([]byte(nunitData)); err != nil || gw.Close() != nil || zw.Close() != nil {
+		t.Fatalf("failed to compress data: %v", err)
+	}
+
+	for name, data := range map[string][]byte{"nunit.xml.gz": gzipData.Bytes(), "artifacts.zip": zipData.Bytes()} {
+		if err := os.WriteFile(filepath.Join(artifacts, name), data, 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		args        []string
+		stdin       string
+		wantCode    int
+		wantSources []string
+		wantStderr  []string
+	}{
+		{
+			name:     "Scan a directory recursively.",
+			args:     []string{"summary", "--fail-on", "none", dir},
+			wantCode: 0,
+			wantSources: []string{
+				filepath.Join(dir, "App.NUnit", "TestResults", "nunit.xml"),
+				resultFile,
+				filepath.Join(dir, "App.Trx", "TestResults", "result.trx"),
+			},
+		},
+		{
+			name:        "Match a glob pattern, with `**`.",
+			args:        []string{"summary", "--fail-on", "none", filepath.Join(dir, "**", "TestResults", "*.trx")},
+			wantCode:    0,
+			wantSources: []string{filepath.Join(dir, "App.Trx", "TestResults", "result.trx")},
+		},
+		{
+			name:        "Pass the same file multiple times.",
+			args:        []string{"summary", "--fail-on", "none", "--logFile", resultFile, resultFile, filepath.Join(dir, "App.Tests", "**", "*.xml")},
+			wantCode:    0,
+			wantSources: []string{resultFile},
+		},
+		{
+			name:     "Scan a directory containing compressed files and archives.",
+			args:     []string{"summary", "--fail-on", "none", artifacts},
+			wantCode: 0,
+			wantSources: []string{
+				filepath.Join(artifacts, "artifacts.zip") + "!/App.Tests/result.xml",
+				filepath.Join(artifacts, "artifacts.zip") + "!/App.Trx/result.trx",
+				filepath.Join(artifacts, "nunit.xml.gz"),
+			},
+		},
+		{
+			name:        "Read an archive from stdin.",
+			args:        []string{"summary", "--fail-on", "none", "-"},
+			stdin:       zipData.String(),
+			wantCode:    0,
+			wantSources: []string{"stdin!/App.Tests/result.xml", "stdin!/App.Trx/result.trx"},
+		},
+		{
+			name:        "Read from stdin.",
+			args:        []string{"summary", "--fail-on", "none", "-"},
+			stdin:       trxData,
+			wantCode:    0,
+			wantSources: []string{"stdin"},
+		},
+		{
+			name:       "Match a glob pattern without any files.",
+			args:       []string{"summary", filepath.Join(dir, "**", "*.html")},
+			wantCode:   2,
+			wantStderr: []string{"no files match", "No LOG files found to process."},
+		},
+	} {
+		var stdout, stderr bytes.Buffer
+
+		a := &app{stdin: strings.NewReader(tc.stdin), stdout: &stdout, stderr: &stderr, getenv: func(string) string { return "" }}
+
+		// ACT.
+		code := a.run(tc.args)
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %v\033[0m\n"+
+			"\033[31mActual:     Exit code %v\033[0m\n\n", tc.name, tc.args, tc.wantCode, code)
+
+		gotSources := make([]string, 0)
+
+		for _, line := range strings.Split(stdout.String(), "\n") {
+			if name, ok := strings.CutPrefix(line, "Input source:         "); ok {
+				gotSources = append(gotSources, strings.TrimSuffix(name, "\r"))
+			}
+		}
+
+		assert.Equal(t, strings.Join(gotSources, "\n"), strings.Join(tc.wantSources, "\n"), "", "\n\n"+
+			"UT Name:    %s\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Sources %v\033[0m\n"+
+			"\033[31mActual:     Sources %v\033[0m\n\n", tc.name, tc.args, tc.wantSources, gotSources)
+
+		for _, want := range tc.wantStderr {
+			assert.Equal(t, strings.Contains(stderr.String(), want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stderr.String())
+		}
+	}
+}
+
+// UT: Parse flags, which are allowed after positional arguments.
+func TestParseFlags(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		args           []string
+		wantFiles      []string
+		wantPositional []string
+	}{
+		{
+			args:           []string{},
+			wantFiles:      []string{},
+			wantPositional: []string{},
+		},
+		{
+			args:           []string{"--logFile", "a.xml", "--logFile", "b.xml,c.xml"},
+			wantFiles:      []string{"a.xml", "b.xml", "c.xml"},
+			wantPositional: []string{},
+		},
+		{
+			args:           []string{"a.xml", "--logFile", "b.xml", "c.xml"},
+			wantFiles:      []string{"b.xml"},
+			wantPositional: []string{"a.xml", "c.xml"},
+		},
+		{
+			args:           []string{"a.xml", "--", "--logFile", "b.xml"},
+			wantFiles:      []string{},
+			wantPositional: []string{"a.xml", "--logFile", "b.xml"},
+		},
+	} {
+		// ARRANGE.
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		files := inputFlags(fs)
+
+		// ACT.
+		positional, err := parseFlags(fs, tc.args)
+
+		// ASSERT.
+		assert.Nil(t, err, "", "\n\n"+
+			"UT Name:    Parse flags, which are allowed after positional arguments.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Error, <nil>\033[0m\n"+
+			"\033[31mActual:     Error, %v\033[0m\n\n", tc.args, err)
+
+		assert.EqualS(t, append([]string{}, *files...), tc.wantFiles, "", "\n\n"+
+			"UT Name:    Parse flags, which are allowed after positional arguments.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Files %v\033[0m\n"+
+			"\033[31mActual:     Files %v\033[0m\n\n", tc.args, tc.wantFiles, *files)
+
+		assert.EqualS(t, positional, tc.wantPositional, "", "\n\n"+
+			"UT Name:    Parse flags, which are allowed after positional arguments.\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Positional arguments %v\033[0m\n"+
+			"\033[31mActual:     Positional arguments %v\033[0m\n\n", tc.args, tc.wantPositional, positional)
+	}
+}
+
+// UT: Print the effective configuration.
+func TestConfig(t *testing.T) {
+	// ARRANGE.
+	repoDir := t.TempDir()
+	workDir := filepath.Join(repoDir, "src", "App.Tests")
+
+	if err := os.MkdirAll(filepath.Join(repoDir, ".git"), 0o700); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	if err := os.MkdirAll(workDir, 0o700); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, configFileName), []byte(`{
+		"thresholdFast": 0.5,
+		"thresholdNormal": 1,
+		"noSplit": ["HostBuilder"],
+		"colors": { "enabled": false }
+	}`), 0o600); err != nil {
+		t.Fatalf("failed to write configuration file: %v", err)
+	}
+
+	invalidConfig := writeTempFile(t, "invalid.json", `{ "thresholdFst": 1 }`)
+	emptyPrefix := writeTempFile(t, "prefix.json", `{ "output": { "pathPrefixes": { "": "src/" } } }`)
+	negativeTop := writeTempFile(t, "top.json", `{ "output": { "top": -1 } }`)
+
+	relativeConfig := []byte(`{ "thresholdFast": 0.01 }`)
+
+	if err := os.WriteFile(filepath.Join(workDir, "relative.json"), relativeConfig, 0o600); err != nil {
+		t.Fatalf("failed to write configuration file: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		workDir    string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:       "Print the standard configuration.",
+			workDir:    t.TempDir(),
+			wantCode:   0,
+			wantStdout: []string{`"thresholdFast": 0.05`, `"thresholdNormal": 0.1`, `"enabled": true`, `"DbSynchronizer"`},
+		},
+		{
+			name:       "Print the configuration discovered in the root of the repository.",
+			workDir:    workDir,
+			wantCode:   0,
+			wantStdout: []string{`"thresholdFast": 0.5`, `"thresholdNormal": 1`, `"HostBuilder"`, `"enabled": false`},
+		},
+		{
+			name:       "Print the configuration, overridden by flags.",
+			workDir:    workDir,
+			args:       []string{"--threshold-normal", "2", "--no-split", "DbSynchronizer,DBSyncer", "--color"},
+			wantCode:   0,
+			wantStdout: []string{`"thresholdFast": 0.5`, `"thresholdNormal": 2`, `"DBSyncer"`, `"enabled": true`},
+		},
+		{
+			name:       "Print an invalid configuration.",
+			workDir:    workDir,
+			args:       []string{"--threshold-fast", "5"},
+			wantCode:   2,
+			wantStderr: []string{"the fast threshold (5) can't be larger than the normal threshold (1)"},
+		},
+		{
+			name:       "Print the configuration of a file with an unknown field.",
+			workDir:    workDir,
+			args:       []string{"--config", invalidConfig},
+			wantCode:   2,
+			wantStderr: []string{"unknown field \"thresholdFst\""},
+		},
+		{
+			name:       "Print the configuration of a file with an empty path prefix.",
+			workDir:    workDir,
+			args:       []string{"--config", emptyPrefix},
+			wantCode:   2,
+			wantStderr: []string{"a path prefix can't be empty"},
+		},
+		{
+			name:       "Print the configuration of a file with a negative number of slowest tests.",
+			workDir:    workDir,
+			args:       []string{"--config", negativeTop},
+			wantCode:   2,
+			wantStderr: []string{"the number of slowest tests can't be negative"},
+		},
+		{
+			name:       "Print the configuration of a file, relative to the working directory.",
+			workDir:    workDir,
+			args:       []string{"--config", "relative.json"},
+			wantCode:   0,
+			wantStdout: []string{`"thresholdFast": 0.01`},
+		},
+	} {
+		// ACT.
+		code, stdout, stderr := runApp(tc.workDir, append([]string{"config"}, tc.args...))
+
+		// ASSERT.
+		assert.Equal(t, code, tc.wantCode, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"Input:      %v\n"+
+			"\033[32mExpected:   Exit code %v\033[0m\n"+
+			"\033[31mActual:     Exit code %v\033[0m\n\n", tc.name, tc.args, tc.wantCode, code)
+
+		for _, want := range tc.wantStdout {
+			assert.Equal(t, strings.Contains(stdout, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stdout containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stdout)
+		}
+
+		for _, want := range tc.wantStderr {
+			assert.Equal(t, strings.Contains(stderr, want), true, "", "\n\n"+
+				"UT Name:    %s\n"+
+				"Input:      %v\n"+
+				"\033[32mExpected:   Stderr containing %q\033[0m\n"+
+				"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.args, want, stderr)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// A command is a subcommand of the application.
+type command struct {
+	name    string                                        // The name of the command, as used on the command line.
+	summary string                                        // A one-line description of the command.
+	run     func(a *app, cmd *command, args []string) int // The function that executes the command.
+}
+
+// A stringList is a flag that can be passed multiple times.
+// Each value can contain multiple items, separated by a comma.
+type stringList []string
+
+// String returns the items of l, separated by a comma.
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set adds the comma-separated item(s) in v to l.
+func (l *stringList) Set(v string) error {
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+
+	return nil
+}
+
+// Returns a new set of flags for cmd.
+// Parse errors and the help of the command are written to the stderr stream of a.
+func (a *app) newFlagSet(cmd *command, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s %s\n\n", appName, cmd.name, usage)
+		fmt.Fprintf(fs.Output(), "%s\n\n", cmd.summary)
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// Parse args using fs and return the positional arguments.
+// Contrary to fs.Parse, flags are allowed after positional arguments. Everything after a `--` argument is treated as
+// a positional argument.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := make([]string, 0)
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		consumed := len(args) - fs.NArg()
+
+		if consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, fs.Args()...), nil
+		}
+
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// Returns the exit code for err, which is returned while parsing flags.
+// Asking for help isn't considered an error.
+func flagErrorCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	return exitUsage
+}
+
+// The flags that are shared by all the commands that read test result(s).
+type commonFlags struct {
+	files  *stringList  // The input file(s).
+	config *configFlags // The flags that override the configuration.
+}
+
+// Register the flags that are shared by all the commands that read test result(s) on fs.
+func newCommonFlags(fs *flag.FlagSet) *commonFlags {
+	return &commonFlags{files: inputFlags(fs), config: newConfigFlags(fs)}
+}
+
+// Parse args using fs and return the input file(s) and the effective configuration.
+// If args are invalid, a message is written to the stderr stream of a and false is returned, together with the exit
+// code of the application.
+func (a *app) parseArgs(fs *flag.FlagSet, cf *commonFlags, args []string) ([]string, configuration, int, bool) {
+	positional, err := parseFlags(fs, args)
+
+	if err != nil {
+		return nil, configuration{}, flagErrorCode(err), false
+	}
+
+	cfg, err := a.resolve(cf.config)
+
+	if err != nil {
+		fmt.Fprintf(a.stderr, "%s: %s\n", a.failed(), err.Error())
+
+		return nil, configuration{}, exitUsage, false
+	}
+
+	inputs, ok := a.inputs(cf.files, positional)
+
+	if !ok {
+		return nil, configuration{}, exitUsage, false
+	}
+
+	return inputs, cfg, exitOK, true
+}
+
+// Returns true if the flag named name is explicitly set in fs, false otherwise.
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+
+	fs.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+
+	return set
+}
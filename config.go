@@ -0,0 +1,426 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kdeconinck/camelcase"
+	"github.com/kdeconinck/slices"
+	"github.com/kdeconinck/words"
+	"github.com/kdeconinck/xunit"
+)
+
+// The name of the configuration file that's discovered in the working directory, or in the root of the repository.
+const configFileName = ".dotnet-test-visualizer.json"
+
+// The values of the `failOn` setting.
+const (
+	failOnFailures = "failures" // Fail when any test failed.
+	failOnErrors   = "errors"   // Fail when any assembly experienced environmental errors.
+	failOnSkips    = "skips"    // Fail when any test was skipped.
+	failOnNone     = "none"     // Never fail because of the test result(s).
+)
+
+// The values of the `showOutput` setting.
+const (
+	showOutputFailed = "failed" // Show the output of the tests which failed.
+	showOutputAll    = "all"    // Show the output of all the tests.
+	showOutputNever  = "never"  // Never show the output of the tests.
+)
+
+// The values of the `groupBy` setting.
+const (
+	groupByTrait      = "trait"      // Group the tests by trait, and within each trait, by (nested) class.
+	groupByCollection = "collection" // Group the tests by the test collection they ran in, and then by (nested) class.
+)
+
+// The speed classifications of a test, based on the thresholds in the configuration.
+const (
+	speedFast   = "fast"   // The test ran at most `thresholdFast` seconds.
+	speedNormal = "normal" // The test ran at most `thresholdNormal` seconds.
+	speedSlow   = "slow"   // The test ran longer than `thresholdNormal` seconds.
+)
+
+// The configuration for the application.
+type configuration struct {
+	ThresholdFast   float32  `json:"thresholdFast"`   // Tests that run at most this number of seconds are fast.
+	ThresholdNormal float32  `json:"thresholdNormal"` // Tests that run at most this number of seconds are normal.
+	NoSplit         []string `json:"noSplit"`         // Words that shouldn't be split when converting names.
+	NoTransform     []string `json:"noTransform"`     // Words that shouldn't be lowercased when converting names.
+	FailOn          []string `json:"failOn"`          // What makes the application exit with a failure.
+	Colors          colors   `json:"colors"`          // The colors used for printing.
+	Output          output   `json:"output"`          // The defaults for the output of the application.
+}
+
+// The colors used for printing.
+// Each color is a sequence of ANSI SGR parameters (for example, "1;32" for bold green).
+type colors struct {
+	Enabled bool   `json:"enabled"` // Whether colors are used.
+	Pass    string `json:"pass"`    // The color of tests which passed.
+	Fail    string `json:"fail"`    // The color of tests which failed.
+	Skip    string `json:"skip"`    // The color of tests which were skipped.
+	NotRun  string `json:"notRun"`  // The color of tests which weren't run.
+	Unknown string `json:"unknown"` // The color of tests with an unknown status.
+	Muted   string `json:"muted"`   // The color of less important information, such as stack traces.
+}
+
+// The defaults for the output of the application.
+type output struct {
+	Header        bool              `json:"header"`        // Whether the ASCII header is printed.
+	Top           int               `json:"top"`           // The number of slowest tests printed by the `stats` command.
+	MarkdownLimit int               `json:"markdownLimit"` // The maximum size of the Markdown output in bytes, 0 for no limit.
+	PathPrefixes  map[string]string `json:"pathPrefixes"`  // The prefixes of paths in annotations, with their replacement.
+	ShowOutput    string            `json:"showOutput"`    // The tests whose output is printed: failed, all or never.
+	OutputLines   int               `json:"outputLines"`   // The maximum number of output lines per test, 0 for no limit.
+	Verbose       bool              `json:"verbose"`       // Whether the environment of each assembly is printed.
+	GroupBy       string            `json:"groupBy"`       // How the tests are grouped when printed: trait or collection.
+}
+
+// The standard configuration for the application.
+var stdConfiguration configuration = configuration{
+	ThresholdFast:   0.05,
+	ThresholdNormal: 0.1,
+	NoSplit:         []string{"HostBuilder", "DBSyncer", "DbSynchronizer"},
+	NoTransform:     []string{"DbSynchronizer", "DBSyncer"},
+	FailOn:          []string{failOnFailures, failOnErrors},
+	Colors: colors{
+		Enabled: true,
+		Pass:    "1;32",
+		Fail:    "1;31",
+		Skip:    "1;33",
+		NotRun:  "1;90",
+		Unknown: "1;35",
+		Muted:   "2",
+	},
+	Output: output{
+		Header:        true,
+		Top:           10,
+		MarkdownLimit: 1024 * 1024,
+		PathPrefixes:  map[string]string{},
+		ShowOutput:    showOutputFailed,
+		OutputLines:   20,
+		GroupBy:       groupByTrait,
+	},
+}
+
+// The flags that override the configuration.
+type configFlags struct {
+	fs              *flag.FlagSet // The set the flags are registered on.
+	path            string        // The path of the configuration file.
+	thresholdFast   float64       // The value of the `--threshold-fast` flag.
+	thresholdNormal float64       // The value of the `--threshold-normal` flag.
+	noSplit         stringList    // The value of the `--no-split` flag.
+	noTransform     stringList    // The value of the `--no-transform` flag.
+	failOn          stringList    // The value of the `--fail-on` flag.
+	color           bool          // The value of the `--color` flag.
+	header          bool          // The value of the `--header` flag.
+	showOutput      string        // The value of the `--show-output` flag.
+	outputLines     int           // The value of the `--output-lines` flag.
+	verbose         bool          // The value of the `--verbose` flag.
+	groupBy         string        // The value of the `--group-by` flag.
+}
+
+// Register the flags that override the configuration on fs.
+func newConfigFlags(fs *flag.FlagSet) *configFlags {
+	cf := &configFlags{fs: fs}
+
+	fs.StringVar(&cf.path, "config", "",
+		"the `file` to read the configuration from (default: a discovered "+configFileName+" file)")
+	fs.Float64Var(&cf.thresholdFast, "threshold-fast", float64(stdConfiguration.ThresholdFast),
+		"tests that run at most this number of `seconds` are fast")
+	fs.Float64Var(&cf.thresholdNormal, "threshold-normal", float64(stdConfiguration.ThresholdNormal),
+		"tests that run at most this number of `seconds` are normal")
+	fs.Var(&cf.noSplit, "no-split",
+		"a `word` that shouldn't be split when converting names (repeatable, comma-separated)")
+	fs.Var(&cf.noTransform, "no-transform",
+		"a `word` that shouldn't be lowercased when converting names (repeatable, comma-separated)")
+	fs.Var(&cf.failOn, "fail-on",
+		"what makes the application exit with code 1: failures, errors, skips or none (comma-separated) "+
+			"(default "+strings.Join(stdConfiguration.FailOn, ",")+")")
+	fs.BoolVar(&cf.color, "color", stdConfiguration.Colors.Enabled, "use colors in the output")
+	fs.BoolVar(&cf.header, "header", stdConfiguration.Output.Header, "print the ASCII header")
+	fs.StringVar(&cf.showOutput, "show-output", stdConfiguration.Output.ShowOutput,
+		"the tests whose captured output is printed: "+showOutputFailed+", "+showOutputAll+" or "+showOutputNever)
+	fs.IntVar(&cf.outputLines, "output-lines", stdConfiguration.Output.OutputLines,
+		"the maximum `number` of lines of captured output that's printed per test (0 for no limit)")
+	fs.BoolVar(&cf.verbose, "verbose", stdConfiguration.Output.Verbose,
+		"print the environment of each assembly, such as its target framework and test framework")
+	fs.StringVar(&cf.groupBy, "group-by", stdConfiguration.Output.GroupBy,
+		"how the printed tests are grouped: "+groupByTrait+" or "+groupByCollection)
+
+	return cf
+}
+
+// Returns the effective configuration.
+// The standard configuration is overridden by the configuration file, which is in turn overridden by the flags that
+// are explicitly set. The effective configuration is applied to the packages that convert names.
+func (cf *configFlags) resolve(workDir string) (configuration, error) {
+	cfg := stdConfiguration.clone()
+	path := cf.path
+
+	if path == "" {
+		path = discoverConfiguration(workDir)
+	} else if !filepath.IsAbs(path) && workDir != "" {
+		path = filepath.Join(workDir, path)
+	}
+
+	if path != "" {
+		if err := loadConfiguration(path, &cfg); err != nil {
+			return configuration{}, err
+		}
+	}
+
+	cf.fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "threshold-fast":
+			cfg.ThresholdFast = float32(cf.thresholdFast)
+		case "threshold-normal":
+			cfg.ThresholdNormal = float32(cf.thresholdNormal)
+		case "no-split":
+			cfg.NoSplit = append([]string{}, cf.noSplit...)
+		case "no-transform":
+			cfg.NoTransform = append([]string{}, cf.noTransform...)
+		case "fail-on":
+			cfg.FailOn = append([]string{}, cf.failOn...)
+		case "color":
+			cfg.Colors.Enabled = cf.color
+		case "header":
+			cfg.Output.Header = cf.header
+		case "show-output":
+			cfg.Output.ShowOutput = cf.showOutput
+		case "output-lines":
+			cfg.Output.OutputLines = cf.outputLines
+		case "verbose":
+			cfg.Output.Verbose = cf.verbose
+		case "group-by":
+			cfg.Output.GroupBy = cf.groupBy
+		}
+	})
+
+	if err := cfg.validate(); err != nil {
+		return configuration{}, err
+	}
+
+	cfg.apply()
+
+	return cfg, nil
+}
+
+// Returns the effective configuration (see configFlags.resolve), and uses its colors for the diagnostic output of a.
+// When the configuration can't be resolved, only the `--color` flag is taken into account (if it's set).
+func (a *app) resolve(cf *configFlags) (configuration, error) {
+	cfg, err := cf.resolve(a.workDir)
+
+	if err != nil {
+		if isFlagSet(cf.fs, "color") {
+			c := stdConfiguration.Colors
+			c.Enabled = cf.color
+			a.colors = &c
+		}
+
+		return configuration{}, err
+	}
+
+	a.colors = &cfg.Colors
+
+	return cfg, nil
+}
+
+// Returns a copy of cfg which doesn't share any memory with cfg.
+func (cfg configuration) clone() configuration {
+	cfg.NoSplit = append([]string{}, cfg.NoSplit...)
+	cfg.NoTransform = append([]string{}, cfg.NoTransform...)
+	cfg.FailOn = append([]string{}, cfg.FailOn...)
+	prefixes := make(map[string]string, len(cfg.Output.PathPrefixes))
+
+	for from, to := range cfg.Output.PathPrefixes {
+		prefixes[from] = to
+	}
+
+	cfg.Output.PathPrefixes = prefixes
+
+	return cfg
+}
+
+// Returns a NON <nil> error if cfg isn't valid.
+func (cfg configuration) validate() error {
+	if cfg.ThresholdFast < 0 || cfg.ThresholdNormal < 0 {
+		return errors.New("the thresholds can't be negative")
+	}
+
+	if cfg.ThresholdFast > cfg.ThresholdNormal {
+		return fmt.Errorf("the fast threshold (%v) can't be larger than the normal threshold (%v)",
+			cfg.ThresholdFast, cfg.ThresholdNormal)
+	}
+
+	if cfg.Output.Top < 0 {
+		return errors.New("the number of slowest tests can't be negative")
+	}
+
+	if cfg.Output.MarkdownLimit < 0 {
+		return errors.New("the Markdown limit can't be negative")
+	}
+
+	if _, ok := cfg.Output.PathPrefixes[""]; ok {
+		return errors.New("a path prefix can't be empty")
+	}
+
+	if cfg.Output.OutputLines < 0 {
+		return errors.New("the number of output lines can't be negative")
+	}
+
+	if v := cfg.Output.ShowOutput; v != showOutputFailed && v != showOutputAll && v != showOutputNever {
+		return fmt.Errorf("invalid value '%s' for showOutput, expected one of: %s, %s, %s",
+			v, showOutputFailed, showOutputAll, showOutputNever)
+	}
+
+	if v := cfg.Output.GroupBy; v != groupByTrait && v != groupByCollection {
+		return fmt.Errorf("invalid value '%s' for groupBy, expected one of: %s, %s", v, groupByTrait, groupByCollection)
+	}
+
+	for _, v := range cfg.FailOn {
+		if v != failOnFailures && v != failOnErrors && v != failOnSkips && v != failOnNone {
+			return fmt.Errorf("invalid value '%s' for failOn, expected one of: %s, %s, %s, %s",
+				v, failOnFailures, failOnErrors, failOnSkips, failOnNone)
+		}
+	}
+
+	return nil
+}
+
+// Returns the speed classification of a test that ran t seconds.
+func (cfg configuration) speed(t float32) string {
+	if t <= cfg.ThresholdFast {
+		return speedFast
+	} else if t <= cfg.ThresholdNormal {
+		return speedNormal
+	}
+
+	return speedSlow
+}
+
+// Returns true if the output of tc is printed, false otherwise.
+func (cfg configuration) showsOutput(tc xunit.TestCase) bool {
+	switch cfg.Output.ShowOutput {
+	case showOutputAll:
+		return tc.Output != ""
+	case showOutputFailed:
+		return tc.Output != "" && tc.Result == xunit.Fail
+	default:
+		return false
+	}
+}
+
+// Returns true if the application should exit with a failure when v happens, false otherwise.
+func (cfg configuration) failsOn(v string) bool {
+	return slices.Contains(cfg.FailOn, v) && !slices.Contains(cfg.FailOn, failOnNone)
+}
+
+// Apply the words of cfg to the packages that convert names.
+func (cfg configuration) apply() {
+	camelcase.NoSplit = cfg.NoSplit
+	words.NoTransform = cfg.NoTransform
+}
+
+// Override the values in cfg with the ones found in the configuration file named path.
+// Values that aren't present in the file are left untouched, unknown values are reported as an error.
+func loadConfiguration(path string, cfg *configuration) error {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	dec := json.NewDecoder(file)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("invalid configuration file '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// Returns the path of the configuration file that applies to dir.
+// The file is searched in dir and its parents, up to (and including) the root of the repository, which is the first
+// directory containing a `.git` entry. If no configuration file is found, an empty string is returned.
+func discoverConfiguration(dir string) string {
+	if dir == "" {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, configFileName)
+
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+
+		parent := filepath.Dir(dir)
+
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// Execute the `config` command.
+func runConfig(a *app, cmd *command, args []string) int {
+	fs := a.newFlagSet(cmd, "[flags]")
+	cf := newConfigFlags(fs)
+
+	if _, err := parseFlags(fs, args); err != nil {
+		return flagErrorCode(err)
+	}
+
+	cfg, err := a.resolve(cf)
+
+	if err != nil {
+		fmt.Fprintf(a.stderr, "%s: %s\n", a.failed(), err.Error())
+
+		return exitUsage
+	}
+
+	data, _ := json.MarshalIndent(cfg, "", "  ")
+	fmt.Fprintln(a.stdout, string(data))
+
+	return exitOK
+}
@@ -0,0 +1,482 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/maps"
+	"github.com/kdeconinck/xunit"
+)
+
+// The default thresholds for considering a test significantly slower (or faster).
+const (
+	stdDiffRatio    = 2.0 // The duration of the test changed by at least this factor.
+	stdDiffMinDelta = 0.1 // The duration of the test changed by at least this number of seconds.
+)
+
+// The functions which write the differences between two test runs to w, for each format supported by `diff`.
+var diffFormats = map[string]func(w io.Writer, cfg configuration, d testDiff) error{
+	formatText: writeDiffText,
+	formatJSON: writeDiffJSON,
+	formatMD:   writeDiffMarkdown,
+}
+
+// A testKey identifies a test across test runs.
+type testKey struct {
+	assembly string // The name of the assembly which contains the test.
+	fullName string // The fully-qualified name of the test.
+}
+
+// A diffEntry is a test which differs between the baseline and the current test run.
+type diffEntry struct {
+	assembly string          // The name of the assembly which contains the test.
+	baseline *xunit.TestCase // The test in the baseline, <nil> if the test was added.
+	current  *xunit.TestCase // The test in the current test run, <nil> if the test was removed.
+}
+
+// A testDiff contains the differences between a baseline and a current test run.
+type testDiff struct {
+	baseline     string      // The name of the file the baseline was loaded from.
+	current      string      // The name of the file the current test run was loaded from.
+	newlyFailing []diffEntry // The tests which failed, but didn't fail in the baseline.
+	newlyPassing []diffEntry // The tests which passed, but failed in the baseline.
+	added        []diffEntry // The tests which aren't part of the baseline.
+	removed      []diffEntry // The tests which aren't part of the current test run.
+	slower       []diffEntry // The tests which are significantly slower than in the baseline.
+	faster       []diffEntry // The tests which are significantly faster than in the baseline.
+}
+
+// A diffSection is a named list of tests in a testDiff.
+type diffSection struct {
+	title    string      // The human-readable name of the section.
+	entries  []diffEntry // The tests in the section.
+	failures bool        // Whether the failures of the tests are rendered.
+}
+
+// Execute the `diff` command.
+func runDiff(a *app, cmd *command, args []string) int {
+	fs := a.newFlagSet(cmd, "[flags] <baseline> <current>")
+	cf := newConfigFlags(fs)
+	format := fs.String("format", formatText, "the output `format`, one of: "+strings.Join(maps.Keys(diffFormats), ", "))
+	out := fs.String("out", "", "write the output to `file`, instead of to stdout")
+	ratio := fs.Float64("ratio", stdDiffRatio,
+		"a test is significantly slower (or faster) when its duration changed by at least this `factor`")
+	minDelta := fs.Float64("min-delta", stdDiffMinDelta,
+		"a test is significantly slower (or faster) when its duration changed by at least this number of `seconds`")
+	positional, err := parseFlags(fs, args)
+
+	if err != nil {
+		return flagErrorCode(err)
+	}
+
+	cfg, err := a.resolve(cf)
+
+	if err != nil {
+		fmt.Fprintf(a.stderr, "%s: %s\n", a.failed(), err.Error())
+
+		return exitUsage
+	}
+
+	write, ok := diffFormats[*format]
+
+	switch {
+	case len(positional) != 2:
+		fmt.Fprintln(a.stderr, a.failed()+": expected a baseline and a current file containing test result(s)")
+		fs.Usage()
+
+		return exitUsage
+	case !ok:
+		fmt.Fprintf(a.stderr, "%s: invalid value '%s' for --format, expected one of: %s\n", a.failed(),
+			*format, strings.Join(maps.Keys(diffFormats), ", "))
+
+		return exitUsage
+	case *ratio < 1 || *minDelta < 0:
+		fmt.Fprintln(a.stderr, a.failed()+": the ratio can't be less than 1 and the delta can't be negative")
+
+		return exitUsage
+	}
+
+	baseline, code, ok := a.loadRun(positional[0], "baseline")
+
+	if !ok {
+		return code
+	}
+
+	current, code, ok := a.loadRun(positional[1], "current test run")
+
+	if !ok {
+		return code
+	}
+
+	d := diffRuns(baseline, current, *ratio, time.Duration(*minDelta*float64(time.Second)))
+
+	if err := a.writeOutput(*out, func(w io.Writer) error { return write(w, cfg, d) }); err != nil {
+		fmt.Fprintf(a.stderr, "%s - %s\n", a.failed(), err.Error())
+
+		return exitOutput
+	}
+
+	if len(d.newlyFailing) > 0 && cfg.failsOn(failOnFailures) {
+		return exitFailures
+	}
+
+	return exitOK
+}
+
+// Returns the test run in file, which is the baseline or the current test run (as described by what).
+// A file which can't be loaded, or which doesn't contain exactly one test run (e.g. an archive with multiple result
+// files), is reported on the stderr stream of a and false is returned, together with the code to exit with.
+func (a *app) loadRun(file, what string) (source, int, bool) {
+	sources, loadFailed := a.load([]string{file})
+
+	if loadFailed {
+		return source{}, exitInput, false
+	}
+
+	if len(sources) != 1 {
+		fmt.Fprintf(a.stderr, "%s: expected a single test run in the %s '%s', found %v\n", a.failed(),
+			what, file, len(sources))
+
+		return source{}, exitUsage, false
+	}
+
+	return sources[0], exitOK, true
+}
+
+// Returns the differences between baseline and current.
+// Tests are matched by the name of their assembly and their fully-qualified name. A test which still passes is
+// significantly slower (or faster) when its duration changed by at least ratio, and by at least minDelta.
+func diffRuns(baseline, current source, ratio float64, minDelta time.Duration) testDiff {
+	d := testDiff{baseline: baseline.name, current: current.name}
+	baseKeys, baseTests := indexTests(baseline.run)
+	curKeys, curTests := indexTests(current.run)
+
+	for _, key := range curKeys {
+		cur := curTests[key]
+		base, ok := baseTests[key]
+		entry := diffEntry{assembly: key.assembly, baseline: &base, current: &cur}
+
+		switch {
+		case !ok:
+			entry.baseline = nil
+			d.added = append(d.added, entry)
+		case base.Result != xunit.Fail && cur.Result == xunit.Fail:
+			d.newlyFailing = append(d.newlyFailing, entry)
+		case base.Result == xunit.Fail && cur.Result == xunit.Pass:
+			d.newlyPassing = append(d.newlyPassing, entry)
+		case cur.Duration-base.Duration >= minDelta && float64(cur.Duration) >= float64(base.Duration)*ratio:
+			d.slower = append(d.slower, entry)
+		case base.Duration-cur.Duration >= minDelta && float64(base.Duration) >= float64(cur.Duration)*ratio:
+			d.faster = append(d.faster, entry)
+		}
+	}
+
+	for _, key := range baseKeys {
+		if _, ok := curTests[key]; !ok {
+			base := baseTests[key]
+			d.removed = append(d.removed, diffEntry{assembly: key.assembly, baseline: &base})
+		}
+	}
+
+	return d
+}
+
+// Returns the key of each test in tRun (in the order the tests are rendered), and the tests by their key.
+func indexTests(tRun xunit.TestRun) ([]testKey, map[testKey]xunit.TestCase) {
+	keys := make([]testKey, 0)
+	tests := make(map[testKey]xunit.TestCase)
+
+	for _, assembly := range tRun.Assemblies {
+		for _, tc := range assembly.Tests() {
+			key := testKey{assembly: assembly.Name, fullName: tc.FullName}
+
+			if _, ok := tests[key]; !ok {
+				keys = append(keys, key)
+				tests[key] = tc
+			}
+		}
+	}
+
+	return keys, tests
+}
+
+// Returns the sections of d, in the order they're rendered.
+func (d testDiff) sections() []diffSection {
+	return []diffSection{
+		{title: "Newly failing", entries: d.newlyFailing, failures: true},
+		{title: "Newly passing", entries: d.newlyPassing},
+		{title: "Added", entries: d.added},
+		{title: "Removed", entries: d.removed},
+		{title: "Slower", entries: d.slower},
+		{title: "Faster", entries: d.faster},
+	}
+}
+
+// Returns the test of e, which is the current test, or the test in the baseline if the test was removed.
+func (e diffEntry) test() xunit.TestCase {
+	if e.current != nil {
+		return *e.current
+	}
+
+	return *e.baseline
+}
+
+// Returns the human-readable path of the test of e, starting with the name of its assembly.
+func (e diffEntry) path() string {
+	tc := e.test()
+
+	return strings.Join(append(append([]string{e.assembly}, tc.Groups...), tc.Name), " › ")
+}
+
+// Returns the duration of the test of e, as the duration in the baseline and the current duration, if both exist.
+func (e diffEntry) duration() string {
+	if e.baseline != nil && e.current != nil {
+		return fmt.Sprintf("%v → %v seconds", e.baseline.Time, e.current.Time)
+	}
+
+	return fmt.Sprintf("%v seconds", e.test().Time)
+}
+
+// Write d to w, as a human-readable tree.
+func writeDiffText(w io.Writer, cfg configuration, d testDiff) error {
+	p := &printer{w: w, cfg: cfg}
+	p.printHeader()
+
+	fmt.Fprintf(p.w, "Baseline:             %s\r\n", d.baseline)
+	fmt.Fprintf(p.w, "Current:              %s\r\n", d.current)
+
+	empty := true
+
+	for _, section := range d.sections() {
+		if len(section.entries) == 0 {
+			continue
+		}
+
+		empty = false
+
+		fmt.Fprintln(p.w, "")
+		fmt.Fprintf(p.w, "  %s (%v):\r\n", section.title, len(section.entries))
+
+		for _, entries := range groupEntries(section.entries) {
+			fmt.Fprintln(p.w, "")
+			fmt.Fprintf(p.w, "    %s\r\n", entries[0].assembly)
+
+			p.printTree(entryGroups(entries), "  ", func(tc xunit.TestCase, indent string) {
+				e := findEntry(entries, tc.FullName)
+
+				fmt.Fprintf(p.w, "%s%s %s %s (%s)\r\n", indent, p.speed(tc.Time), p.status(tc.Result), tc.Name, e.duration())
+
+				if section.failures && tc.Failure != nil {
+					p.printFailure(tc.Failure, indent+"     ")
+				}
+			})
+		}
+	}
+
+	if empty {
+		fmt.Fprintln(p.w, "")
+		fmt.Fprintln(p.w, "  No differences.")
+	}
+
+	return nil
+}
+
+// Returns entries, grouped by the assembly which contains their test (in the order the assemblies appear in entries).
+func groupEntries(entries []diffEntry) [][]diffEntry {
+	groups := make([][]diffEntry, 0)
+	idx := make(map[string]int)
+
+	for _, e := range entries {
+		if i, ok := idx[e.assembly]; ok {
+			groups[i] = append(groups[i], e)
+
+			continue
+		}
+
+		idx[e.assembly] = len(groups)
+		groups = append(groups, []diffEntry{e})
+	}
+
+	return groups
+}
+
+// Returns the tests of entries, grouped by their nested class(es) (without grouping them by trait).
+func entryGroups(entries []diffEntry) []*xunit.TestGroup {
+	tests := make([]xunit.TestCase, 0, len(entries))
+
+	for _, e := range entries {
+		tc := e.test()
+		tc.Traits = nil
+		tests = append(tests, tc)
+	}
+
+	return xunit.GroupTests(tests)
+}
+
+// Returns the entry in entries whose test is named fullName.
+func findEntry(entries []diffEntry, fullName string) diffEntry {
+	for _, e := range entries {
+		if e.test().FullName == fullName {
+			return e
+		}
+	}
+
+	return diffEntry{}
+}
+
+// A jsonDiff is the top-level object of the JSON output of `diff`.
+type jsonDiff struct {
+	Baseline     string          `json:"baseline"`     // The name of the file the baseline was loaded from.
+	Current      string          `json:"current"`      // The name of the file the current test run was loaded from.
+	NewlyFailing []jsonDiffEntry `json:"newlyFailing"` // The tests which failed, but didn't fail in the baseline.
+	NewlyPassing []jsonDiffEntry `json:"newlyPassing"` // The tests which passed, but failed in the baseline.
+	Added        []jsonDiffEntry `json:"added"`        // The tests which aren't part of the baseline.
+	Removed      []jsonDiffEntry `json:"removed"`      // The tests which aren't part of the current test run.
+	Slower       []jsonDiffEntry `json:"slower"`       // The tests which are significantly slower than in the baseline.
+	Faster       []jsonDiffEntry `json:"faster"`       // The tests which are significantly faster than in the baseline.
+}
+
+// A jsonDiffEntry is a test which differs between the baseline and the current test run.
+type jsonDiffEntry struct {
+	Assembly string          `json:"assembly"`           // The name of the assembly which contains the test.
+	FullName string          `json:"fullName"`           // The fully-qualified name of the test.
+	Name     string          `json:"name"`               // The human-readable name of the test.
+	Path     []string        `json:"path"`               // The human-readable names of the (nested) groups of the test.
+	Baseline *jsonDiffResult `json:"baseline,omitempty"` // The result in the baseline, absent if the test was added.
+	Current  *jsonDiffResult `json:"current,omitempty"`  // The current result, absent if the test was removed.
+}
+
+// A jsonDiffResult is the result of a test in one of the test runs.
+type jsonDiffResult struct {
+	Result  string       `json:"result"`            // One of "pass", "fail", "skip", "notRun" or "unknown".
+	Time    float32      `json:"time"`              // The number of seconds that the test took to run.
+	Failure *jsonFailure `json:"failure,omitempty"` // The reason why the test failed.
+}
+
+// Write d to w, as JSON.
+func writeDiffJSON(w io.Writer, cfg configuration, d testDiff) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(jsonDiff{
+		Baseline:     d.baseline,
+		Current:      d.current,
+		NewlyFailing: newJSONDiffEntries(d.newlyFailing),
+		NewlyPassing: newJSONDiffEntries(d.newlyPassing),
+		Added:        newJSONDiffEntries(d.added),
+		Removed:      newJSONDiffEntries(d.removed),
+		Slower:       newJSONDiffEntries(d.slower),
+		Faster:       newJSONDiffEntries(d.faster),
+	})
+}
+
+// Returns entries as a list of jsonDiffEntry.
+func newJSONDiffEntries(entries []diffEntry) []jsonDiffEntry {
+	res := make([]jsonDiffEntry, 0, len(entries))
+
+	for _, e := range entries {
+		tc := e.test()
+
+		res = append(res, jsonDiffEntry{
+			Assembly: e.assembly,
+			FullName: tc.FullName,
+			Name:     tc.Name,
+			Path:     append([]string{}, tc.Groups...),
+			Baseline: newJSONDiffResult(e.baseline),
+			Current:  newJSONDiffResult(e.current),
+		})
+	}
+
+	return res
+}
+
+// Returns tc as a jsonDiffResult, or <nil> if tc is <nil>.
+func newJSONDiffResult(tc *xunit.TestCase) *jsonDiffResult {
+	if tc == nil {
+		return nil
+	}
+
+	res := &jsonDiffResult{Result: resultName(tc.Result), Time: tc.Time}
+
+	if tc.Failure != nil {
+		res.Failure = &jsonFailure{
+			ExceptionType: tc.Failure.ExceptionType,
+			Message:       tc.Failure.Message,
+			StackTrace:    tc.Failure.StackTrace,
+		}
+	}
+
+	return res
+}
+
+// Write d to w, as a Markdown summary.
+// The summary contains a table with the number of tests per section, followed by the tests of each section. When the
+// summary exceeds the limit of cfg, the tests that don't fit are omitted, and a notice is added instead.
+func writeDiffMarkdown(w io.Writer, cfg configuration, d testDiff) error {
+	md := &markdown{limit: cfg.Output.MarkdownLimit}
+
+	md.add("## Test differences\n\n", 0)
+	md.add(fmt.Sprintf("Comparing `%s` with the baseline `%s`.\n\n", d.current, d.baseline), 0)
+	md.add("| Change | Tests |\n", 0)
+	md.add("| :-- | --: |\n", 0)
+
+	for _, section := range d.sections() {
+		md.add(fmt.Sprintf("| %s | %v |\n", section.title, len(section.entries)), 0)
+	}
+
+	for _, section := range d.sections() {
+		if len(section.entries) == 0 {
+			continue
+		}
+
+		if !md.add(fmt.Sprintf("\n### %s\n\n", section.title), 0) {
+			md.omitted += len(section.entries)
+
+			continue
+		}
+
+		for _, e := range section.entries {
+			item := fmt.Sprintf("- **%s** (%s)\n", escapeMarkdown(e.path()), e.duration())
+
+			if section.failures {
+				item += markdownMessage(e.current.Failure)
+			}
+
+			if !md.add(item, 0) {
+				md.omitted++
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, md.String())
+
+	return err
+}
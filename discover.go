@@ -0,0 +1,213 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kdeconinck/loader"
+)
+
+// The name of the input which refers to the stdin stream, instead of to a file.
+const stdinName = "-"
+
+// Returns the files which are referred to by args.
+// Each argument is either a file, a directory (which is scanned recursively for result files, see loader.IsResultFile),
+// a glob pattern (where `**` matches any number of directories) or `-` for the stdin stream. A file that's referred to
+// more than once is only returned once. Patterns that don't match any file are reported on the stderr stream of a.
+func (a *app) discover(args []string) []string {
+	files := make([]string, 0, len(args))
+	seen := make(map[string]bool)
+
+	for _, arg := range args {
+		matches, err := expand(arg)
+
+		if err != nil {
+			fmt.Fprintf(a.stderr, "\033[1;33mWarning\033[0m: %s\n", err.Error())
+
+			continue
+		}
+
+		for _, file := range matches {
+			key := file
+
+			if abs, err := filepath.Abs(file); err == nil && file != stdinName {
+				key = abs
+			}
+
+			if !seen[key] {
+				seen[key] = true
+				files = append(files, file)
+			}
+		}
+	}
+
+	return files
+}
+
+// Returns the files which are referred to by arg.
+// A file that doesn't exist is returned as is, so that the error is reported when it's loaded.
+func expand(arg string) ([]string, error) {
+	if arg == stdinName {
+		return []string{arg}, nil
+	}
+
+	if hasMeta(arg) {
+		return glob(arg)
+	}
+
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		return scanDir(arg)
+	}
+
+	return []string{arg}, nil
+}
+
+// Returns the result files in dir (or in any of its subdirectories), in lexical order.
+func scanDir(dir string) ([]string, error) {
+	files := make([]string, 0)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && loader.IsResultFile(p) {
+			files = append(files, p)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// Returns the files which match pattern, in lexical order.
+// Directories that match pattern are scanned for result files.
+func glob(pattern string) ([]string, error) {
+	base, segments := splitPattern(filepath.ToSlash(pattern))
+	files := make([]string, 0)
+
+	err := filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == base {
+				return err
+			}
+
+			return nil
+		}
+
+		rel, _ := filepath.Rel(base, p)
+
+		if rel == "." || !matchSegments(segments, strings.Split(filepath.ToSlash(rel), "/")) {
+			return nil
+		}
+
+		if !d.IsDir() {
+			files = append(files, p)
+
+			return nil
+		}
+
+		dirFiles, err := scanDir(p)
+		files = append(files, dirFiles...)
+
+		if err != nil {
+			return err
+		}
+
+		return filepath.SkipDir
+	})
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files match '%s'", pattern)
+	}
+
+	return files, nil
+}
+
+// Returns the directory in which pattern starts matching, and the segments of pattern relative to that directory.
+// The directory consists of the leading segments of pattern, which don't contain any special characters.
+func splitPattern(pattern string) (string, []string) {
+	segments := strings.Split(pattern, "/")
+	idx := 0
+
+	for idx < len(segments)-1 && !hasMeta(segments[idx]) {
+		idx++
+	}
+
+	base := strings.Join(segments[:idx], "/")
+
+	switch {
+	case base == "" && strings.HasPrefix(pattern, "/"):
+		base = "/"
+	case base == "":
+		base = "."
+	}
+
+	return filepath.FromSlash(base), segments[idx:]
+}
+
+// Returns true if name (split into segments) matches the segments of a pattern, false otherwise.
+// The segment `**` matches any number of segments (including none), other segments are matched using path.Match.
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for idx := 0; idx <= len(name); idx++ {
+			if matchSegments(pattern[1:], name[idx:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	ok, _ := path.Match(pattern[0], name[0])
+
+	return ok && matchSegments(pattern[1:], name[1:])
+}
+
+// Returns true if v contains any of the special characters of a glob pattern, false otherwise.
+func hasMeta(v string) bool {
+	return strings.ContainsAny(v, "*?[")
+}
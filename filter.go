@@ -0,0 +1,143 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kdeconinck/maps"
+	"github.com/kdeconinck/xunit"
+)
+
+// The results that can be passed to the `--only` flag.
+var onlyResults = map[string]xunit.Result{
+	"passed":  xunit.Pass,
+	"failed":  xunit.Fail,
+	"skipped": xunit.Skip,
+	"notrun":  xunit.NotRun,
+}
+
+// The flags that narrow the test(s) that are written.
+type filterFlags struct {
+	only        stringList // The value of the `--only` flag.
+	traits      stringList // The value of the `--trait` flag.
+	assemblies  stringList // The value of the `--assembly` flag.
+	name        *string    // The value of the `--name` flag.
+	excludeName *string    // The value of the `--exclude-name` flag.
+}
+
+// Register the flags that narrow the test(s) that are written on fs.
+func newFilterFlags(fs *flag.FlagSet) *filterFlags {
+	ff := &filterFlags{
+		name:        fs.String("name", "", "only write the test(s) whose (fully-qualified) name matches `regex`"),
+		excludeName: fs.String("exclude-name", "", "don't write the test(s) whose (fully-qualified) name matches `regex`"),
+	}
+
+	fs.Var(&ff.only, "only",
+		"only write the test(s) with one of these `results`: "+strings.Join(maps.Keys(onlyResults), ", ")+
+			" (repeatable, comma-separated)")
+	fs.Var(&ff.traits, "trait",
+		"only write the test(s) with one of these `name=value` traits (repeatable, comma-separated)")
+	fs.Var(&ff.assemblies, "assembly",
+		"only write the test(s) of these `assemblies`, with or without their path (repeatable, comma-separated)")
+
+	return ff
+}
+
+// Returns the filter that's described by ff.
+// If any of the flags is invalid, a message is written to the stderr stream of a and false is returned.
+func (a *app) parseFilter(ff *filterFlags) (xunit.Filter, bool) {
+	filter := xunit.Filter{Assemblies: ff.assemblies}
+
+	for _, v := range ff.only {
+		result, ok := onlyResults[strings.ToLower(v)]
+
+		if !ok {
+			fmt.Fprintf(a.stderr, "%s: invalid value '%s' for --only, expected one of: %s\n", a.failed(),
+				v, strings.Join(maps.Keys(onlyResults), ", "))
+
+			return xunit.Filter{}, false
+		}
+
+		filter.Results = append(filter.Results, result)
+	}
+
+	for _, v := range ff.traits {
+		name, value, ok := strings.Cut(v, "=")
+
+		if !ok || name == "" {
+			fmt.Fprintf(a.stderr, "%s: invalid value '%s' for --trait, expected name=value\n", a.failed(), v)
+
+			return xunit.Filter{}, false
+		}
+
+		filter.Traits = append(filter.Traits, xunit.Trait{Name: name, Value: value})
+	}
+
+	var ok bool
+
+	if filter.Name, ok = a.compile("name", *ff.name); !ok {
+		return xunit.Filter{}, false
+	}
+
+	if filter.ExcludeName, ok = a.compile("exclude-name", *ff.excludeName); !ok {
+		return xunit.Filter{}, false
+	}
+
+	return filter, true
+}
+
+// Returns expr, the value of the flag named name, as a regular expression, or <nil> if expr is empty.
+// If expr is invalid, a message is written to the stderr stream of a and false is returned.
+func (a *app) compile(name, expr string) (*regexp.Regexp, bool) {
+	if expr == "" {
+		return nil, true
+	}
+
+	re, err := regexp.Compile(expr)
+
+	if err != nil {
+		fmt.Fprintf(a.stderr, "%s: invalid value '%s' for --%s: %s\n", a.failed(), expr, name, err.Error())
+
+		return nil, false
+	}
+
+	return re, true
+}
+
+// Returns a copy of sources, which only contains the test(s) that are selected by filter.
+func filterSources(filter xunit.Filter, sources []source) []source {
+	filtered := make([]source, 0, len(sources))
+
+	for _, src := range sources {
+		filtered = append(filtered, source{name: src.name, run: filter.Apply(src.run)})
+	}
+
+	return filtered
+}
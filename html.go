@@ -0,0 +1,67 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+)
+
+// The template of the HTML report.
+//
+//go:embed assets/report.html
+var htmlTemplate string
+
+// The HTML report, which is a single static file (with inline CSS and JavaScript), so it can be opened offline.
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"symbol": func(result string) string {
+		switch result {
+		case "pass":
+			return "✓"
+		case "fail":
+			return "⛌"
+		case "skip":
+			return "⊘"
+		case "notRun":
+			return "○"
+		default:
+			return "?"
+		}
+	},
+}).Parse(htmlTemplate))
+
+// Write the test result(s) in sources to w, as an HTML report.
+// The report contains the same information as the JSON report.
+func writeHTML(w io.Writer, cfg configuration, sources []source) error {
+	return htmlReport.Execute(w, struct {
+		Generator string     // The name of the application that generated the report.
+		Report    jsonReport // The test result(s).
+	}{
+		Generator: appName,
+		Report:    newJSONReport(cfg, sources),
+	})
+}
@@ -0,0 +1,112 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/kdeconinck/loader"
+	"github.com/kdeconinck/xunit"
+)
+
+// A source is a test run, together with the name of the file (or the entry of an archive) it was loaded from.
+type source struct {
+	name string        // The name of the file (or the entry of an archive) the test run was loaded from.
+	run  xunit.TestRun // The test run.
+}
+
+// Register the flag(s) for passing input file(s) on fs.
+// The returned list is filled when fs is parsed.
+func inputFlags(fs *flag.FlagSet) *stringList {
+	files := new(stringList)
+
+	fs.Var(files, "logFile",
+		"a `file` containing test result(s) in xUnit's v2+ XML or v3 JSON, NUnit's v3 XML, JUnit's XML or TRX format,"+
+			" optionally compressed with gzip or stored in a zip archive, a directory to scan for *.xml, *.trx, *.gz and"+
+			" *.zip files, a glob pattern (** matches any number of directories) or - for stdin (repeatable,"+
+			" comma-separated)")
+
+	return files
+}
+
+// Returns the input file(s), which are either passed using the `--logFile` flag, or as positional arguments.
+// Directories and glob patterns are expanded to the files they refer to (see `discover`). If there aren't any input
+// files, a message is written to the stderr stream of a and false is returned.
+func (a *app) inputs(files *stringList, positional []string) ([]string, bool) {
+	inputs := a.discover(append([]string(*files), positional...))
+
+	if len(inputs) == 0 {
+		fmt.Fprintln(a.stderr, a.failed()+": No LOG files found to process.")
+		fmt.Fprintln(a.stderr, "        Use the `--logFile` argument to pass a file containing logs in a supported format.")
+		fmt.Fprintln(a.stderr, "        The supported formats are xUnit's v2+ XML or v3 JSON, NUnit's v3 XML, JUnit's XML and TRX.")
+		fmt.Fprintln(a.stderr, "        To specify multiple files, pass the argument once for each log file, or pass a directory or a glob.")
+		fmt.Fprintln(a.stderr, "")
+
+		return nil, false
+	}
+
+	return inputs, true
+}
+
+// Load each file in files.
+// Files (or entries of an archive) that can't be loaded are reported on the stderr stream of a and are excluded from
+// the result. If any of them couldn't be loaded, true is returned as well.
+func (a *app) load(files []string) ([]source, bool) {
+	sources := make([]source, 0, len(files))
+	failed := false
+
+	for _, file := range files {
+		var inputs []loader.Input
+		var err error
+
+		if file == stdinName {
+			inputs, err = loader.LoadNamed("stdin", a.stdin)
+		} else {
+			inputs, err = loader.LoadFile(file)
+		}
+
+		if err != nil {
+			fmt.Fprintf(a.stderr, "%s - %s\n", a.failed(), err.Error())
+			failed = true
+
+			continue
+		}
+
+		for _, input := range inputs {
+			if input.Err != nil {
+				fmt.Fprintf(a.stderr, "%s - %s\n", a.failed(), input.Err.Error())
+				failed = true
+
+				continue
+			}
+
+			sources = append(sources, source{name: input.Name, run: input.Run})
+		}
+	}
+
+	return sources, failed
+}
@@ -0,0 +1,141 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package junit contains functions for reading and writing XML files containing test result(s) in JUnit's XML format.
+// This is the format that's understood by most CI systems (such as GitLab, Jenkins and CircleCI).
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// A testSuites is the top-level element of a document containing multiple test suites.
+type testSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr,omitempty"`
+	Tests    string      `xml:"tests,attr,omitempty"`
+	Failures string      `xml:"failures,attr,omitempty"`
+	Errors   string      `xml:"errors,attr,omitempty"`
+	Skipped  string      `xml:"skipped,attr,omitempty"`
+	Time     string      `xml:"time,attr,omitempty"`
+	Suites   []testSuite `xml:"testsuite"`
+}
+
+// A testSuite contains the result of running a group of tests.
+// In JUnit's XML format, a test suite can contain other test suites.
+type testSuite struct {
+	Name       string      `xml:"name,attr"`
+	Tests      string      `xml:"tests,attr"`
+	Failures   string      `xml:"failures,attr"`
+	Errors     string      `xml:"errors,attr"`
+	Skipped    string      `xml:"skipped,attr"`
+	Time       string      `xml:"time,attr,omitempty"`
+	Timestamp  string      `xml:"timestamp,attr,omitempty"`
+	Hostname   string      `xml:"hostname,attr,omitempty"`
+	Properties *properties `xml:"properties"`
+	Suites     []testSuite `xml:"testsuite"`
+	Cases      []testCase  `xml:"testcase"`
+	SystemOut  *text       `xml:"system-out"`
+	SystemErr  *text       `xml:"system-err"`
+}
+
+// A properties contains the properties of a test suite or a test case.
+type properties struct {
+	Items []property `xml:"property"`
+}
+
+// A property is a single name/value pair attached to a test suite or a test case.
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// A testCase contains the result of running a single test.
+type testCase struct {
+	Name       string      `xml:"name,attr"`
+	ClassName  string      `xml:"classname,attr,omitempty"`
+	Time       string      `xml:"time,attr,omitempty"`
+	Properties *properties `xml:"properties"`
+	Failure    *problem    `xml:"failure"`
+	Error      *problem    `xml:"error"`
+	Skipped    *problem    `xml:"skipped"`
+	SystemOut  *text       `xml:"system-out"`
+	SystemErr  *text       `xml:"system-err"`
+}
+
+// A problem contains the reason why a test failed, errored or was skipped.
+type problem struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",cdata"`
+}
+
+// A text is a block of (multi-line) text, such as the output of a test.
+type text struct {
+	Value string `xml:",cdata"`
+}
+
+// Returns the test suites, constructed from the data in rdr.
+// The root element is either a <testsuites> element or a single <testsuite> element.
+func unmarshal(rdr io.Reader) ([]testSuite, error) {
+	dec := xml.NewDecoder(rdr)
+
+	for {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		el, ok := tok.(xml.StartElement)
+
+		if !ok {
+			continue
+		}
+
+		switch el.Name.Local {
+		case "testsuites":
+			var suites testSuites
+
+			if err := dec.DecodeElement(&suites, &el); err != nil {
+				return nil, err
+			}
+
+			return suites.Suites, nil
+		case "testsuite":
+			var suite testSuite
+
+			if err := dec.DecodeElement(&suite, &el); err != nil {
+				return nil, err
+			}
+
+			return []testSuite{suite}, nil
+		default:
+			return nil, fmt.Errorf("unexpected root element <%s>, expected <testsuites> or <testsuite>", el.Name.Local)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "junit" package.
+package junit_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/assert"
+	"github.com/kdeconinck/junit"
+	"github.com/kdeconinck/xunit"
+)
+
+// UT: Load an XML file containing a test result in JUnit's XML format.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		xmlData string
+		want    xunit.TestRun
+		wantErr bool
+	}{
+		{
+			xmlData: "<assemblies />",
+			wantErr: true,
+		},
+		{
+			xmlData: "<testsuite name=\"app\" tests=\"1\">\n" +
+				"  <testcase name=\"testAdd\" classname=\"com.example.CalculatorTest$Add\">\n" +
+				"    <skipped />\n" +
+				"  </testcase>\n" +
+				"</testsuite>",
+			want: xunit.TestRun{
+				Assemblies: []xunit.Assembly{
+					{
+						Name:         "app",
+						SkippedCount: 1,
+						TotalCount:   1,
+						TestGroups: []*xunit.TestGroup{
+							{
+								Name:  "",
+								Tests: []xunit.TestCase{},
+								Groups: []*xunit.TestGroup{
+									{
+										Name: "Calculator test",
+										Groups: []*xunit.TestGroup{
+											{
+												Name: "Add",
+												Tests: []xunit.TestCase{
+													{Name: "test add", FullName: "com.example.CalculatorTest$Add.testAdd", Result: xunit.Skip, Groups: []string{"Calculator test", "Add"}},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			xmlData: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+				"<testsuites>\n" +
+				"  <testsuite name=\"App.Tests.dll\" time=\"1.75\" timestamp=\"2023-10-07T18:53:19\" hostname=\"WIN11\">\n" +
+				"    <testcase name=\"Returns sum\" classname=\"Calculator tests.Add\" time=\"0.25\">\n" +
+				"      <properties><property name=\"Category\" value=\"Unit\" /></properties>\n" +
+				"      <system-out>Adding numbers.\n</system-out>\n" +
+				"    </testcase>\n" +
+				"    <testcase name=\"DividesByZero\" classname=\"NS.CalculatorTests\" time=\"1.5\">\n" +
+				"      <error message=\"Attempted to divide by zero.\" type=\"System.DivideByZeroException\"><![CDATA[   at NS.CalculatorTests.DividesByZero()]]></error>\n" +
+				"    </testcase>\n" +
+				"    <testcase name=\"Is skipped\">\n" +
+				"      <skipped message=\"Not implemented yet.\" />\n" +
+				"    </testcase>\n" +
+				"  </testsuite>\n" +
+				"  <testsuite name=\"Other.Tests.dll\">\n" +
+				"    <testsuite name=\"Nested\">\n" +
+				"      <testcase name=\"Test\" time=\"0.1\"><failure /></testcase>\n" +
+				"      <testsuite name=\"Deeper\"><testcase name=\"Test\" /></testsuite>\n" +
+				"    </testsuite>\n" +
+				"  </testsuite>\n" +
+				"</testsuites>",
+			want: xunit.TestRun{
+				Computer:      "WIN11",
+				Timestamp:     "2023-10-07T18:53:19",
+				TimestampTime: time.Date(2023, 10, 7, 18, 53, 19, 0, time.UTC),
+				Assemblies: []xunit.Assembly{
+					{
+						Name:         "App.Tests.dll",
+						PassedCount:  1,
+						FailedCount:  1,
+						SkippedCount: 1,
+						TotalCount:   3,
+						RunDate:      "2023-10-07",
+						RunTime:      "18:53:19",
+						Time:         1.75,
+						RunStart:     time.Date(2023, 10, 7, 18, 53, 19, 0, time.UTC),
+						Duration:     1750 * time.Millisecond,
+						TestGroups: []*xunit.TestGroup{
+							{
+								Name: "",
+								Tests: []xunit.TestCase{
+									{Name: "Is skipped", FullName: "Is skipped", Result: xunit.Skip, Reason: "Not implemented yet."},
+								},
+								Groups: []*xunit.TestGroup{
+									{
+										Name: "Calculator tests",
+										Tests: []xunit.TestCase{
+											{
+												Name:     "Divides by zero",
+												FullName: "NS.CalculatorTests.DividesByZero",
+												Result:   xunit.Fail,
+												Time:     1.5,
+												Duration: 1500 * time.Millisecond,
+												Failure: &xunit.Failure{
+													ExceptionType: "System.DivideByZeroException",
+													Message:       "Attempted to divide by zero.",
+													StackTrace:    "   at NS.CalculatorTests.DividesByZero()",
+												},
+												Groups: []string{"Calculator tests"},
+											},
+										},
+									},
+								},
+							},
+							{
+								Name:  "Category - Unit",
+								Tests: []xunit.TestCase{},
+								Groups: []*xunit.TestGroup{
+									{
+										Name: "Calculator tests",
+										Groups: []*xunit.TestGroup{
+											{
+												Name: "Add",
+												Tests: []xunit.TestCase{
+													{
+														Name:     "Returns sum",
+														FullName: "Calculator tests.Add.Returns sum",
+														Result:   xunit.Pass,
+														Time:     0.25,
+														Duration: 250 * time.Millisecond,
+														Output:   "Adding numbers.",
+														Groups:   []string{"Calculator tests", "Add"},
+														Traits:   []xunit.Trait{{Name: "Category", Value: "Unit"}},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Name:        "Other.Tests.dll",
+						PassedCount: 1,
+						FailedCount: 1,
+						TotalCount:  2,
+						Time:        0.1,
+						Duration:    100 * time.Millisecond,
+						TestGroups: []*xunit.TestGroup{
+							{
+								Name: "",
+								Tests: []xunit.TestCase{
+									{
+										Name: "Test", FullName: "Nested.Test", Result: xunit.Fail, Time: 0.1, Duration: 100 * time.Millisecond,
+										Failure: &xunit.Failure{},
+									},
+									{Name: "Test", FullName: "Nested.Deeper.Test", Result: xunit.Pass},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	} {
+		// HELPER FUNCTIONS.
+		fmtValue := func(v xunit.TestRun) string {
+			b, _ := json.MarshalIndent(v, "", "  ")
+			res := strings.Replace(string(b), "\n", "\n            ", -1)
+
+			return res
+		}
+
+		fmtXml := func(v string) string {
+			v = strings.Replace(v, "\n", "\n            ", -1)
+
+			return v
+		}
+
+		// ARRANGE.
+		rdr := strings.NewReader(tc.xmlData)
+
+		// ACT.
+		got, err := junit.Load(rdr)
+
+		// ASSERT.
+		if tc.wantErr {
+			assert.NotNil(t, err, "", "\n\n"+
+				"UT Name:    Parse an invalid XML file containing a .NET test result in JUnit's XML format.\n"+
+				"XML Input:  %s\n"+
+				"\033[32mExpected:   Error, NOT <nil>\033[0m\n"+
+				"\033[31mActual:     Error, %v\033[0m\n\n", fmtXml(tc.xmlData), err)
+		}
+
+		if !tc.wantErr {
+			assert.Nil(t, err, "", "\n\n"+
+				"UT Name:    Parse a valid XML file containing a .NET test result in JUnit's XML format.\n"+
+				"XML Input:  %s\n"+
+				"\033[32mExpected:   Error, <nil>\033[0m\n"+
+				"\033[31mActual:     Error, %v\033[0m\n\n", fmtXml(tc.xmlData), err)
+		}
+
+		assert.EqualFn(t, got, tc.want, func(got xunit.TestRun, want xunit.TestRun) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Parse an XML file containing a .NET test result in JUnit's XML format.\n"+
+			"XML Input:  %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n",
+			fmtXml(tc.xmlData), fmtValue(tc.want), fmtValue(got))
+	}
+}
+
+// UT: Load an XML file in JUnit's XML format, which contains tests with the same name in different test suites.
+func TestLoad_SameNames(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	rdr := strings.NewReader("<testsuite name=\"App.Tests.dll\">\n" +
+		"  <testsuite name=\"First\"><testcase name=\"Adds numbers\" /></testsuite>\n" +
+		"  <testsuite name=\"Second\"><testcase name=\"Adds numbers\"><failure /></testcase></testsuite>\n" +
+		"</testsuite>")
+
+	// ACT.
+	tRun, err := junit.Load(rdr)
+
+	// ASSERT.
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Load an XML file in JUnit's XML format, which contains tests with the same name in different test suites.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+
+	var failed int
+
+	tests := tRun.Assemblies[0].Tests()
+
+	for _, tc := range tests {
+		if tc.Result == xunit.Fail {
+			failed++
+		}
+	}
+
+	assert.Equal(t, [2]int{len(tests), failed}, [2]int{2, 1}, "", "\n\n"+
+		"UT Name:    Load an XML file in JUnit's XML format, which contains tests with the same name in different test suites.\n"+
+		"\033[32mExpected:   Tests: 2, Failed: 1\033[0m\n"+
+		"\033[31mActual:     Tests: %v, Failed: %v\033[0m\n\n", len(tests), failed)
+}
+
+// UT: Write a test result in JUnit's XML format.
+func TestWrite(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	tRun := xunit.TestRun{
+		Computer: "WIN11",
+		Assemblies: []xunit.Assembly{
+			{
+				Name:         "App.Tests.dll",
+				PassedCount:  1,
+				FailedCount:  2,
+				SkippedCount: 1,
+				NotRunCount:  1,
+				TotalCount:   5,
+				RunDate:      "2023-10-07",
+				RunTime:      "20:53:19",
+				Time:         2,
+			},
+		},
+	}
+
+	tests := []xunit.TestCase{
+		{
+			Name:   "Returns sum",
+			Result: xunit.Pass,
+			Time:   0.25,
+			Output: "Adding numbers.",
+			Groups: []string{"Calculator tests", "Add"},
+			Traits: []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Category", Value: "Math"}},
+		},
+		{
+			Name:    "Divides by zero",
+			Result:  xunit.Fail,
+			Time:    1.5,
+			Failure: &xunit.Failure{ExceptionType: "System.DivideByZeroException", Message: "Attempted to divide by zero.", StackTrace: "   at NS.CalculatorTests.DividesByZero()"},
+			Groups:  []string{"Calculator tests"},
+		},
+		{Name: "Is skipped", Result: xunit.Skip, Reason: "Not implemented yet."},
+		{Name: "Is not run", Result: xunit.NotRun},
+	}
+
+	tRun.Assemblies[0].TestGroups = xunit.GroupTests(tests)
+
+	want := "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+		"<testsuites tests=\"4\" failures=\"1\" errors=\"0\" skipped=\"2\" time=\"2\">\n" +
+		"  <testsuite name=\"App.Tests.dll\" tests=\"4\" failures=\"1\" errors=\"0\" skipped=\"2\" time=\"2\" timestamp=\"2023-10-07T20:53:19\" hostname=\"WIN11\">\n" +
+		"    <testcase name=\"Is skipped\" time=\"0\">\n" +
+		"      <skipped message=\"Not implemented yet.\"></skipped>\n" +
+		"    </testcase>\n" +
+		"    <testcase name=\"Is not run\" time=\"0\">\n" +
+		"      <skipped></skipped>\n" +
+		"    </testcase>\n" +
+		"    <testcase name=\"Divides by zero\" classname=\"Calculator tests\" time=\"1.5\">\n" +
+		"      <failure message=\"Attempted to divide by zero.\" type=\"System.DivideByZeroException\"><![CDATA[   at NS.CalculatorTests.DividesByZero()]]></failure>\n" +
+		"    </testcase>\n" +
+		"    <testcase name=\"Returns sum\" classname=\"Calculator tests.Add\" time=\"0.25\">\n" +
+		"      <properties>\n" +
+		"        <property name=\"Category\" value=\"Unit\"></property>\n" +
+		"        <property name=\"Category\" value=\"Math\"></property>\n" +
+		"      </properties>\n" +
+		"      <system-out><![CDATA[Adding numbers.]]></system-out>\n" +
+		"    </testcase>\n" +
+		"  </testsuite>\n" +
+		"</testsuites>\n"
+
+	var b strings.Builder
+
+	// ACT.
+	err := junit.Write(&b, tRun)
+
+	// ASSERT.
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Write a test result in JUnit's XML format.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+
+	assert.Equal(t, b.String(), want, "", "\n\n"+
+		"UT Name:    Write a test result in JUnit's XML format.\n"+
+		"\033[32mExpected:   %s\033[0m\n"+
+		"\033[31mActual:     %s\033[0m\n\n", want, b.String())
+}
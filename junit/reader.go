@@ -0,0 +1,211 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package junit contains functions for reading and writing XML files containing test result(s) in JUnit's XML format.
+// This is the format that's understood by most CI systems (such as GitLab, Jenkins and CircleCI).
+package junit
+
+import (
+	"io"
+	"strings"
+
+	"github.com/kdeconinck/camelcase"
+	"github.com/kdeconinck/words"
+	"github.com/kdeconinck/xunit"
+)
+
+// Load returns a TestRun constructed from the data in rdr.
+// Each top-level test suite is converted into an assembly, and the class of each test is converted into its groups.
+// The properties of each test are converted into traits.
+func Load(rdr io.Reader) (xunit.TestRun, error) {
+	suites, err := unmarshal(rdr)
+
+	if err != nil {
+		return xunit.TestRun{}, err
+	}
+
+	return readTestRun(suites), nil
+}
+
+// Read suites into a TestRun.
+func readTestRun(suites []testSuite) xunit.TestRun {
+	tRun := xunit.TestRun{Assemblies: make([]xunit.Assembly, 0, len(suites))}
+
+	for idx := range suites {
+		if tRun.Computer == "" {
+			tRun.Computer = suites[idx].Hostname
+		}
+
+		if tRun.Timestamp == "" {
+			tRun.Timestamp = suites[idx].Timestamp
+		}
+
+		tRun.Assemblies = append(tRun.Assemblies, suites[idx].toAssembly())
+	}
+
+	tRun.ParseTimes()
+
+	return tRun
+}
+
+// Returns suite as an Assembly.
+// The counts are calculated from the tests, since not every tool that writes JUnit's XML format fills them in.
+func (suite *testSuite) toAssembly() xunit.Assembly {
+	tests := suite.testCases("")
+
+	assembly := xunit.Assembly{
+		Name:       suite.Name,
+		TotalCount: len(tests),
+		TestGroups: xunit.GroupTests(tests),
+	}
+
+	assembly.Duration, _ = xunit.ParseSeconds(strings.ReplaceAll(suite.Time, ",", ""))
+	assembly.Time = float32(assembly.Duration.Seconds())
+
+	if date, clock, ok := strings.Cut(suite.Timestamp, "T"); ok {
+		assembly.RunDate = date
+		assembly.RunTime = clock
+	}
+
+	for _, tc := range tests {
+		if suite.Time == "" {
+			assembly.Time += tc.Time
+			assembly.Duration += tc.Duration
+		}
+
+		switch tc.Result {
+		case xunit.Pass:
+			assembly.PassedCount++
+		case xunit.Fail:
+			assembly.FailedCount++
+		case xunit.Skip:
+			assembly.SkippedCount++
+		}
+	}
+
+	return assembly
+}
+
+// Returns the tests in suite (and in all of its nested test suites).
+// The nested test suites are qualified by path, which contains the names of the test suites they're nested in
+// (separated by a `.`), excluding the outermost test suite.
+func (suite *testSuite) testCases(path string) []xunit.TestCase {
+	tests := make([]xunit.TestCase, 0, len(suite.Cases))
+
+	for idx := range suite.Cases {
+		tests = append(tests, suite.Cases[idx].toTestCase(path))
+	}
+
+	for idx := range suite.Suites {
+		nested := suite.Suites[idx].Name
+
+		if path != "" {
+			nested = path + "." + nested
+		}
+
+		tests = append(tests, suite.Suites[idx].testCases(nested)...)
+	}
+
+	return tests
+}
+
+// Returns t as a TestCase, which is part of the (nested) test suite in path.
+// A test without a class is qualified by path, since its name is only unique within its test suite.
+func (t *testCase) toTestCase(path string) xunit.TestCase {
+	tc := xunit.NewTestCase(t.Name)
+	tc.Result = xunit.Pass
+	tc.Duration, _ = xunit.ParseSeconds(strings.ReplaceAll(t.Time, ",", ""))
+	tc.Time = float32(tc.Duration.Seconds())
+	tc.Output = strings.TrimSpace(strings.Join([]string{t.SystemOut.value(), t.SystemErr.value()}, "\n"))
+
+	switch {
+	case t.ClassName != "":
+		tc.FullName = t.ClassName + "." + t.Name
+		tc.Groups = classGroups(t.ClassName)
+	case path != "":
+		tc.FullName = path + "." + t.Name
+	}
+
+	switch {
+	case t.Failure != nil || t.Error != nil:
+		p := t.Failure
+
+		if p == nil {
+			p = t.Error
+		}
+
+		tc.Result = xunit.Fail
+		tc.Failure = &xunit.Failure{ExceptionType: p.Type, Message: strings.TrimSpace(p.Message), StackTrace: p.Text}
+	case t.Skipped != nil:
+		tc.Result = xunit.Skip
+		tc.Reason = strings.TrimSpace(t.Skipped.Message)
+	}
+
+	for _, prop := range t.Properties.items() {
+		tc.Traits = append(tc.Traits, xunit.Trait{Name: prop.Name, Value: prop.Value})
+	}
+
+	return tc
+}
+
+// Returns the groups of a test in the class named className.
+// When className contains a space, it's considered to be a path of human-readable group names, separated by a `.`
+// (as written by Write). Otherwise, it's the fully qualified name of a class, in which case the class and its outer
+// class(es) (separated by a `+` or a `$`) are converted into human-readable group names.
+func classGroups(className string) []string {
+	if strings.Contains(className, " ") {
+		return strings.Split(className, ".")
+	}
+
+	classes := strings.FieldsFunc(className[strings.LastIndex(className, ".")+1:], func(r rune) bool {
+		return r == '+' || r == '$'
+	})
+
+	groups := make([]string, 0, len(classes))
+
+	for _, class := range classes {
+		groups = append(groups, words.ToSentence(camelcase.Split(class)))
+	}
+
+	return groups
+}
+
+// Returns the properties in props, or <nil> if props is <nil>.
+func (props *properties) items() []property {
+	if props == nil {
+		return nil
+	}
+
+	return props.Items
+}
+
+// Returns the value of t, or an empty string if t is <nil>.
+func (t *text) value() string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Value
+}
@@ -0,0 +1,156 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package junit contains functions for reading and writing XML files containing test result(s) in JUnit's XML format.
+// This is the format that's understood by most CI systems (such as GitLab, Jenkins and CircleCI).
+package junit
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kdeconinck/xunit"
+)
+
+// Write writes tRun to w in JUnit's XML format.
+// Each assembly is written as a test suite, and the human-readable groups of each test are written as its class,
+// separated by a `.`. Tests that weren't run are written as skipped tests, and the traits of each test are written as
+// its properties.
+func Write(w io.Writer, tRun xunit.TestRun) error {
+	doc := testSuites{Suites: make([]testSuite, 0, len(tRun.Assemblies))}
+
+	var cases []testCase
+	var time float32
+
+	for _, assembly := range tRun.Assemblies {
+		suite := writeAssembly(assembly, tRun.Computer)
+		doc.Suites = append(doc.Suites, suite)
+
+		cases = append(cases, suite.Cases...)
+		time += assembly.Time
+	}
+
+	doc.Tests, doc.Failures, doc.Errors, doc.Skipped = countCases(cases)
+	doc.Time = formatTime(time)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// Returns assembly as a test suite, which ran on the computer named hostname.
+func writeAssembly(assembly xunit.Assembly, hostname string) testSuite {
+	suite := testSuite{
+		Name:     assembly.Name,
+		Time:     formatTime(assembly.Time),
+		Hostname: hostname,
+	}
+
+	if assembly.RunDate != "" && assembly.RunTime != "" {
+		suite.Timestamp = assembly.RunDate + "T" + assembly.RunTime
+	}
+
+	for _, tc := range assembly.Tests() {
+		suite.Cases = append(suite.Cases, writeTestCase(tc))
+	}
+
+	suite.Tests, suite.Failures, suite.Errors, suite.Skipped = countCases(suite.Cases)
+
+	return suite
+}
+
+// Returns the number of test cases in cases, and how many of them failed, errored and were skipped.
+// The numbers are counted from the test cases themselves, so that they always match what's written.
+func countCases(cases []testCase) (tests, failures, errors, skipped string) {
+	var failed, errored, skippedCount int
+
+	for _, t := range cases {
+		if t.Failure != nil {
+			failed++
+		}
+
+		if t.Error != nil {
+			errored++
+		}
+
+		if t.Skipped != nil {
+			skippedCount++
+		}
+	}
+
+	return strconv.Itoa(len(cases)), strconv.Itoa(failed), strconv.Itoa(errored), strconv.Itoa(skippedCount)
+}
+
+// Returns tc as a test case.
+func writeTestCase(tc xunit.TestCase) testCase {
+	t := testCase{
+		Name:      tc.Name,
+		ClassName: strings.Join(tc.Groups, "."),
+		Time:      formatTime(tc.Time),
+	}
+
+	switch tc.Result {
+	case xunit.Fail:
+		t.Failure = &problem{}
+
+		if tc.Failure != nil {
+			t.Failure = &problem{Message: tc.Failure.Message, Type: tc.Failure.ExceptionType, Text: tc.Failure.StackTrace}
+		}
+	case xunit.Skip, xunit.NotRun:
+		t.Skipped = &problem{Message: tc.Reason}
+	}
+
+	if tc.Output != "" {
+		t.SystemOut = &text{Value: tc.Output}
+	}
+
+	if len(tc.Traits) > 0 {
+		t.Properties = &properties{Items: make([]property, 0, len(tc.Traits))}
+	}
+
+	for _, trait := range tc.Traits {
+		t.Properties.Items = append(t.Properties.Items, property{Name: trait.Name, Value: trait.Value})
+	}
+
+	return t
+}
+
+// Returns seconds, formatted as a decimal number.
+func formatTime(seconds float32) string {
+	return strconv.FormatFloat(float64(seconds), 'f', -1, 32)
+}
@@ -0,0 +1,3 @@
+module github.com/kdeconinck/loader
+
+go 1.21.0
@@ -0,0 +1,259 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package loader contains functions for loading files containing .NET test result(s) in any of the supported formats,
+// which is detected based on the content of the file. Files can be compressed with gzip, or stored in a zip archive.
+package loader
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kdeconinck/junit"
+	"github.com/kdeconinck/nunit"
+	"github.com/kdeconinck/slices"
+	"github.com/kdeconinck/trx"
+	"github.com/kdeconinck/xunit"
+)
+
+// The loaders for each supported format, indexed by the name of the root element of the format.
+var loaders = map[string]func(rdr io.Reader) (xunit.TestRun, error){
+	"assemblies": xunit.Load,
+	"TestRun":    trx.Load,
+	"test-run":   nunit.Load,
+	"testsuites": junit.Load,
+	"testsuite":  junit.Load,
+}
+
+// The extensions of the files which contain test result(s), optionally followed by `.gz`.
+var resultExtensions = []string{".xml", ".trx"}
+
+// The signatures at the start of data in a given format.
+var (
+	gzipSignature = []byte{0x1f, 0x8b}
+	zipSignature  = []byte("PK\x03\x04")
+)
+
+// The separator between the name of a zip archive and the name of one of its entries.
+const entrySeparator = "!/"
+
+// An Input is a test run, together with the name of the input it was loaded from.
+type Input struct {
+	Name string        // The name of the input. The name of an entry of a zip archive is `<archive>!/<entry>`.
+	Run  xunit.TestRun // The test run, which is empty if it couldn't be loaded.
+	Err  error         // The reason why the test run couldn't be loaded, <nil> if it's loaded.
+}
+
+// IsResultFile returns true if the file named name is expected to contain test result(s), false otherwise.
+// These are files with an `.xml` or a `.trx` extension (optionally compressed, with an additional `.gz` extension), and
+// zip archives.
+func IsResultFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+
+	if ext == ".zip" {
+		return true
+	}
+
+	if ext == ".gz" {
+		ext = strings.ToLower(filepath.Ext(strings.TrimSuffix(name, filepath.Ext(name))))
+	}
+
+	return slices.Contains(resultExtensions, ext)
+}
+
+// Load returns the TestRun stored in rdr.
+// Data that's compressed with gzip is decompressed transparently. The format of the data is detected based on the name
+// of the root element, or as xUnit's v3 JSON message stream if the data starts with a `{`.
+func Load(rdr io.Reader) (xunit.TestRun, error) {
+	bRdr := bufio.NewReader(rdr)
+
+	if hasSignature(bRdr, gzipSignature) {
+		gzRdr, err := gzip.NewReader(bRdr)
+
+		if err != nil {
+			return xunit.TestRun{}, err
+		}
+
+		defer gzRdr.Close()
+
+		bRdr = bufio.NewReader(gzRdr)
+	}
+
+	if isJSON(bRdr) {
+		return xunit.LoadJSON(bRdr)
+	}
+
+	root, dataRdr := rootElement(bRdr)
+	load, ok := loaders[root]
+
+	if !ok {
+		return xunit.TestRun{}, fmt.Errorf("unsupported format, unknown root element <%s>", root)
+	}
+
+	return load(dataRdr)
+}
+
+// LoadFile returns the test run(s) stored in the file named name.
+// If the file can't be opened, a NON <nil> error is returned. Otherwise, an Input is returned for the file, or for each
+// entry of a zip archive (see LoadNamed).
+func LoadFile(name string) ([]Input, error) {
+	f, err := os.Open(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return LoadNamed(name, f)
+}
+
+// LoadNamed returns the test run(s) stored in rdr, which is known by name.
+// If the data in rdr is a zip archive, an Input is returned for each entry which is expected to contain test result(s)
+// (see IsResultFile). If the archive is invalid, or if it doesn't contain any of these entries, a NON <nil> error is
+// returned. Otherwise, a single Input is returned.
+func LoadNamed(name string, rdr io.Reader) ([]Input, error) {
+	bRdr := bufio.NewReader(rdr)
+
+	if !hasSignature(bRdr, zipSignature) {
+		tRun, err := Load(bRdr)
+
+		if err != nil {
+			err = fmt.Errorf("%s: %w", name, err)
+		}
+
+		return []Input{{Name: name, Run: tRun, Err: err}}, nil
+	}
+
+	data, err := io.ReadAll(bRdr)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	inputs := loadArchive(name, archive)
+
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("%s: the archive doesn't contain any test result(s)", name)
+	}
+
+	return inputs, nil
+}
+
+// Returns an Input for each entry of archive (which is known by name) that's expected to contain test result(s).
+// Nested archives aren't supported.
+func loadArchive(name string, archive *zip.Reader) []Input {
+	inputs := make([]Input, 0)
+
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() || !IsResultFile(entry.Name) || strings.EqualFold(filepath.Ext(entry.Name), ".zip") {
+			continue
+		}
+
+		input := Input{Name: name + entrySeparator + entry.Name}
+		input.Run, input.Err = loadEntry(entry)
+
+		if input.Err != nil {
+			input.Err = fmt.Errorf("%s: %w", input.Name, input.Err)
+		}
+
+		inputs = append(inputs, input)
+	}
+
+	return inputs
+}
+
+// Returns the TestRun stored in entry (an entry of a zip archive).
+func loadEntry(entry *zip.File) (xunit.TestRun, error) {
+	rdr, err := entry.Open()
+
+	if err != nil {
+		return xunit.TestRun{}, err
+	}
+
+	defer rdr.Close()
+
+	return Load(rdr)
+}
+
+// Returns true if the data in rdr starts with signature, without consuming any data.
+func hasSignature(rdr *bufio.Reader, signature []byte) bool {
+	buf, _ := rdr.Peek(len(signature))
+
+	return bytes.Equal(buf, signature)
+}
+
+// Returns true if the first character in rdr (ignoring whitespace) is a `{`, without consuming any data.
+func isJSON(rdr *bufio.Reader) bool {
+	for n := 1; ; n++ {
+		buf, err := rdr.Peek(n)
+
+		if err != nil {
+			return false
+		}
+
+		switch buf[n-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		default:
+			return buf[n-1] == '{'
+		}
+	}
+}
+
+// Returns the name of the root element of the XML data in rdr, and a reader which returns all the data in rdr.
+// If the root element can't be found, "assemblies" is returned, so that xUnit's decoder reports why the data is
+// invalid.
+func rootElement(rdr io.Reader) (string, io.Reader) {
+	var buf bytes.Buffer
+
+	dec := xml.NewDecoder(io.TeeReader(rdr, &buf))
+
+	for {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return "assemblies", io.MultiReader(&buf, rdr)
+		}
+
+		if el, ok := tok.(xml.StartElement); ok {
+			return el.Name.Local, io.MultiReader(&buf, rdr)
+		}
+	}
+}
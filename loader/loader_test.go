@@ -0,0 +1,214 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "loader" package.
+package loader_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/kdeconinck/assert"
+	"github.com/kdeconinck/loader"
+)
+
+// The content of a file containing a .NET test result in xUnit's v2+ XML format.
+const xmlData = "<assemblies><assembly name=\"App.dll\" total=\"1\" passed=\"1\" /></assemblies>"
+
+// The content of a file containing a .NET test result in TRX format.
+const trxData = "<TestRun><Results><UnitTestResult testId=\"t1\" testName=\"NS.TestClass.ReturnsTrue\" outcome=\"Passed\" />" +
+	"</Results><TestDefinitions><UnitTest id=\"t1\"><TestMethod codeBase=\"/src/App.Trx.dll\" /></UnitTest>" +
+	"</TestDefinitions></TestRun>"
+
+// Returns data, compressed with gzip.
+func compress(t *testing.T, data string) string {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+
+	if _, err := w.Write([]byte(data)); err != nil || w.Close() != nil {
+		t.Fatalf("failed to compress data: %v", err)
+	}
+
+	return buf.String()
+}
+
+// Returns a zip archive, containing an entry for each name/data pair in entries.
+func archive(t *testing.T, entries ...string) string {
+	var buf bytes.Buffer
+
+	w := zip.NewWriter(&buf)
+
+	for idx := 0; idx < len(entries); idx += 2 {
+		f, err := w.Create(entries[idx])
+
+		if err == nil {
+			_, err = f.Write([]byte(entries[idx+1]))
+		}
+
+		if err != nil {
+			t.Fatalf("failed to create archive: %v", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+
+	return buf.String()
+}
+
+// UT: Load a .NET test result, detecting its format and compression.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name         string
+		data         string
+		wantAssembly string
+		wantErr      string
+	}{
+		{name: "Load a file in xUnit's v2+ XML format.", data: xmlData, wantAssembly: "App.dll"},
+		{name: "Load a file in TRX format.", data: trxData, wantAssembly: "App.Trx.dll"},
+		{name: "Load a compressed file in xUnit's v2+ XML format.", data: compress(t, xmlData), wantAssembly: "App.dll"},
+		{
+			name:         "Load a message stream in xUnit's v3 JSON format.",
+			data:         "\n" + `{"$type":"test-assembly-starting","AssemblyUniqueID":"a1","AssemblyPath":"/src/App.V3.dll"}`,
+			wantAssembly: "App.V3.dll",
+		},
+		{name: "Load a file in an unknown format.", data: "<unknown />", wantErr: "unknown root element <unknown>"},
+		{name: "Load a corrupt compressed file.", data: compress(t, xmlData)[:12], wantErr: "unexpected EOF"},
+	} {
+		// ACT.
+		got, err := loader.Load(strings.NewReader(tc.data))
+
+		// ASSERT.
+		gotErr, gotAssembly := "", ""
+
+		if err != nil {
+			gotErr = err.Error()
+		}
+
+		if len(got.Assemblies) > 0 {
+			gotAssembly = got.Assemblies[0].Name
+		}
+
+		assert.Equal(t, gotAssembly, tc.wantAssembly, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"\033[32mExpected:   Assembly %q\033[0m\n"+
+			"\033[31mActual:     Assembly %q\033[0m\n\n", tc.name, tc.wantAssembly, gotAssembly)
+
+		assert.Equal(t, strings.Contains(gotErr, tc.wantErr), true, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"\033[32mExpected:   Error containing %q\033[0m\n"+
+			"\033[31mActual:     Error %q\033[0m\n\n", tc.name, tc.wantErr, gotErr)
+	}
+}
+
+// UT: Load the .NET test result(s) in a zip archive.
+func TestLoadNamed_Archive(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	data := archive(t,
+		"App.Tests/TestResults/result.xml", xmlData,
+		"App.Trx/TestResults/result.trx.gz", compress(t, trxData),
+		"App.Trx/bin/App.Trx.deps.json", "{}",
+		"App.Broken/TestResults/result.xml", "<assemblies>")
+
+	want := []string{
+		"artifacts.zip!/App.Tests/TestResults/result.xml: App.dll",
+		"artifacts.zip!/App.Trx/TestResults/result.trx.gz: App.Trx.dll",
+		"artifacts.zip!/App.Broken/TestResults/result.xml: malformed XML",
+	}
+
+	// ACT.
+	inputs, err := loader.LoadNamed("artifacts.zip", strings.NewReader(data))
+
+	// ASSERT.
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Load the .NET test result(s) in a zip archive.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+
+	got := make([]string, 0, len(inputs))
+
+	for _, input := range inputs {
+		if input.Err != nil {
+			got = append(got, input.Name+": "+strings.SplitAfter(input.Err.Error(), "malformed XML")[0][len(input.Name)+2:])
+
+			continue
+		}
+
+		got = append(got, input.Name+": "+input.Run.Assemblies[0].Name)
+	}
+
+	assert.Equal(t, strings.Join(got, "\n"), strings.Join(want, "\n"), "", "\n\n"+
+		"UT Name:    Load the .NET test result(s) in a zip archive.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", want, got)
+}
+
+// UT: Load a zip archive without any .NET test result(s).
+func TestLoadNamed_EmptyArchive(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ACT.
+	_, err := loader.LoadNamed("artifacts.zip", strings.NewReader(archive(t, "readme.md", "# Readme")))
+
+	// ASSERT.
+	assert.NotNil(t, err, "", "\n\n"+
+		"UT Name:    Load a zip archive without any .NET test result(s).\n"+
+		"\033[32mExpected:   Error, NOT <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+}
+
+// UT: Determine if a file is expected to contain .NET test result(s).
+func TestIsResultFile(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for name, want := range map[string]bool{
+		"result.xml":     true,
+		"result.TRX":     true,
+		"result.xml.gz":  true,
+		"artifacts.zip":  true,
+		"result.json":    false,
+		"result.json.gz": false,
+		"archive.tar.gz": false,
+	} {
+		// ACT.
+		got := loader.IsResultFile(name)
+
+		// ASSERT.
+		assert.Equal(t, got, want, "", "\n\n"+
+			"UT Name:    Determine if a file is expected to contain .NET test result(s).\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", name, want, got)
+	}
+}
@@ -0,0 +1,215 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kdeconinck/xunit"
+)
+
+// The number of bytes that are reserved for the notice that's added when the Markdown output is truncated.
+const markdownNoticeSize = 256
+
+// A markdown is a Markdown document that's limited in size.
+type markdown struct {
+	b         strings.Builder // The content of the document.
+	limit     int             // The maximum size (in bytes) of the document, 0 for no limit.
+	truncated bool            // Whether any content was omitted because of the limit.
+	omitted   int             // The number of tests that were omitted because of the limit.
+}
+
+// Add s to md if it fits, taking into account that reserved bytes are still needed after s.
+// If s doesn't fit, md is marked as truncated and false is returned.
+func (md *markdown) add(s string, reserved int) bool {
+	if md.limit > 0 && md.b.Len()+len(s)+reserved+markdownNoticeSize > md.limit {
+		md.truncated = true
+
+		return false
+	}
+
+	md.b.WriteString(s)
+
+	return true
+}
+
+// Write a Markdown summary of the test result(s) in sources to w.
+// The summary contains a table with the result of each assembly, followed by a collapsible section per assembly with
+// the failing tests and their messages. When the summary exceeds the limit of cfg, the content that doesn't fit is
+// omitted, and a notice is added instead.
+func writeMarkdown(w io.Writer, cfg configuration, sources []source) error {
+	md := &markdown{limit: cfg.Output.MarkdownLimit}
+
+	md.add("## Test results\n\n", 0)
+	md.add("| | Assembly | Passed | Failed | Skipped | Not run | Time |\n", 0)
+	md.add("| :-: | :-- | --: | --: | --: | --: | --: |\n", 0)
+
+	for _, src := range sources {
+		for _, assembly := range src.run.Assemblies {
+			md.add(fmt.Sprintf("| %s | %s | %v | %v | %v | %v | %vs |\n", assemblyStatus(assembly),
+				escapeMarkdown(assembly.Name), assembly.PassedCount, assembly.FailedCount, assembly.SkippedCount,
+				assembly.NotRunCount, assembly.Time), 0)
+		}
+	}
+
+	for _, src := range sources {
+		for _, assembly := range src.run.Assemblies {
+			md.addFailures(assembly)
+		}
+	}
+
+	_, err := io.WriteString(w, md.String())
+
+	return err
+}
+
+// Returns the content of md, followed by a notice when any content was omitted because of the limit.
+func (md *markdown) String() string {
+	if !md.truncated {
+		return md.b.String()
+	}
+
+	notice := fmt.Sprintf("\n> [!WARNING]\n> The summary is truncated, since it exceeds %v bytes.", md.limit)
+
+	if md.omitted > 0 {
+		notice += fmt.Sprintf(" %v test(s) aren't listed.", md.omitted)
+	}
+
+	return md.b.String() + notice + "\n"
+}
+
+// Add a collapsible section with the failing tests of assembly (and their messages) to md.
+func (md *markdown) addFailures(assembly xunit.Assembly) {
+	failures := make([]xunit.TestCase, 0, assembly.FailedCount)
+
+	for _, tc := range assembly.Tests() {
+		if tc.Result == xunit.Fail {
+			failures = append(failures, tc)
+		}
+	}
+
+	if len(failures) == 0 {
+		return
+	}
+
+	const end = "\n</details>\n"
+
+	head := fmt.Sprintf("\n<details>\n<summary>⛌ %s: %v failing test(s)</summary>\n\n",
+		escapeMarkdown(assembly.Name), len(failures))
+
+	if !md.add(head, len(end)) {
+		md.omitted += len(failures)
+
+		return
+	}
+
+	for _, tc := range failures {
+		if !md.add(markdownFailure(tc), len(end)) {
+			md.omitted++
+		}
+	}
+
+	md.b.WriteString(end)
+}
+
+// Returns tc (a failing test) as a Markdown list item, including the message of its failure.
+func markdownFailure(tc xunit.TestCase) string {
+	name := strings.Join(append(append([]string{}, tc.Groups...), tc.Name), " › ")
+
+	return fmt.Sprintf("- **%s** (%v seconds)\n", escapeMarkdown(name), tc.Time) + markdownMessage(tc.Failure)
+}
+
+// Returns the exception type and the message of failure as a fenced code block, which is indented to belong to a list
+// item. If failure doesn't have an exception type nor a message, an empty string is returned.
+func markdownMessage(failure *xunit.Failure) string {
+	if failure == nil || (failure.ExceptionType == "" && failure.Message == "") {
+		return ""
+	}
+
+	var b strings.Builder
+
+	msg := failure.Message
+
+	if failure.ExceptionType != "" {
+		msg = failure.ExceptionType + ": " + msg
+	}
+
+	fence := strings.Repeat("`", max(3, longestRun(msg, '`')+1))
+
+	fmt.Fprintf(&b, "\n  %stext\n", fence)
+
+	for _, line := range strings.Split(strings.TrimRight(msg, "\n"), "\n") {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+
+	fmt.Fprintf(&b, "  %s\n", fence)
+
+	return b.String()
+}
+
+// Returns the symbol that represents the result of assembly.
+func assemblyStatus(assembly xunit.Assembly) string {
+	if assembly.FailedCount > 0 {
+		return "⛌"
+	}
+
+	return "✓"
+}
+
+// Returns v, with the characters that have a special meaning in Markdown (or in a table) escaped.
+func escapeMarkdown(v string) string {
+	var b strings.Builder
+
+	for _, r := range v {
+		if strings.ContainsRune("\\`*_[]<>|#", r) {
+			b.WriteRune('\\')
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Returns the length of the longest run of r in v.
+func longestRun(v string, r rune) int {
+	longest, current := 0, 0
+
+	for _, c := range v {
+		if c != r {
+			current = 0
+
+			continue
+		}
+
+		current++
+		longest = max(longest, current)
+	}
+
+	return longest
+}
@@ -0,0 +1,67 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"strings"
+
+	"github.com/kdeconinck/xunit"
+)
+
+// Execute the `merge` command.
+func runMerge(a *app, cmd *command, args []string) int {
+	fs := a.newFlagSet(cmd, "[flags] [file ...]")
+	cf := newCommonFlags(fs)
+	of := newOutputFlags(fs, formatXUnit)
+	inputs, cfg, code, ok := a.parseArgs(fs, cf, args)
+
+	if !ok {
+		return code
+	}
+
+	sources, loadFailed := a.load(inputs)
+	merged := []source{mergeSources(sources)}
+
+	if code, ok := a.write(of, cfg, merged); !ok {
+		return code
+	}
+
+	return exitCode(cfg, merged, loadFailed)
+}
+
+// Returns a single source which contains the test run(s) in sources, merged into a single test run.
+// Assemblies with the same name (such as the shards of a test suite) are merged into a single assembly.
+func mergeSources(sources []source) source {
+	names := make([]string, 0, len(sources))
+	runs := make([]xunit.TestRun, 0, len(sources))
+
+	for _, src := range sources {
+		names = append(names, src.name)
+		runs = append(runs, src.run)
+	}
+
+	return source{name: strings.Join(names, ", "), run: xunit.Merge(runs...)}
+}
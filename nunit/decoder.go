@@ -0,0 +1,109 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package nunit contains functions for parsing XML files containing .NET test result(s) in NUnit's v3 XML format.
+// More information regarding this format can be found @ https://docs.nunit.org/articles/nunit/technical-notes/usage/Test-Result-XML-Format.html.
+package nunit
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// A testRun is the top-level element of the document.
+type testRun struct {
+	XMLName   xml.Name    `xml:"test-run"`
+	ID        string      `xml:"id,attr"`
+	StartTime string      `xml:"start-time,attr"`
+	EndTime   string      `xml:"end-time,attr"`
+	Duration  string      `xml:"duration,attr"`
+	Suites    []testSuite `xml:"test-suite"`
+}
+
+// A testSuite contains the result of running a group of tests (an assembly, a namespace, a fixture, ...).
+type testSuite struct {
+	Type        string      `xml:"type,attr"`
+	Name        string      `xml:"name,attr"`
+	FullName    string      `xml:"fullname,attr"`
+	Result      string      `xml:"result,attr"`
+	Label       string      `xml:"label,attr"`
+	StartTime   string      `xml:"start-time,attr"`
+	EndTime     string      `xml:"end-time,attr"`
+	Duration    string      `xml:"duration,attr"`
+	Environment environment `xml:"environment"`
+	Properties  []property  `xml:"properties>property"`
+	Suites      []testSuite `xml:"test-suite"`
+	Cases       []testCase  `xml:"test-case"`
+}
+
+// An environment contains information about the environment in which an assembly was run.
+type environment struct {
+	MachineName string `xml:"machine-name,attr"`
+	User        string `xml:"user,attr"`
+	UserDomain  string `xml:"user-domain,attr"`
+}
+
+// A property is a single name/value pair attached to a test suite or a test case.
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// A testCase contains the result of running a single test.
+type testCase struct {
+	Name       string     `xml:"name,attr"`
+	FullName   string     `xml:"fullname,attr"`
+	MethodName string     `xml:"methodname,attr"`
+	ClassName  string     `xml:"classname,attr"`
+	Result     string     `xml:"result,attr"`
+	Label      string     `xml:"label,attr"`
+	Duration   string     `xml:"duration,attr"`
+	Properties []property `xml:"properties>property"`
+	Failure    failure    `xml:"failure"`
+	Reason     reason     `xml:"reason"`
+	Output     string     `xml:"output"`
+}
+
+// A failure contains information about a test failure.
+type failure struct {
+	Message    string `xml:"message"`
+	StackTrace string `xml:"stack-trace"`
+}
+
+// A reason contains the reason why a test wasn't run.
+type reason struct {
+	Message string `xml:"message"`
+}
+
+// Returns a testRun, constructed from the data in rdr.
+func unmarshal(rdr io.Reader) (testRun, error) {
+	var run testRun
+
+	if err := xml.NewDecoder(rdr).Decode(&run); err != nil {
+		return testRun{}, err
+	}
+
+	return run, nil
+}
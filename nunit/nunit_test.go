@@ -0,0 +1,254 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "nunit" package.
+package nunit_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/assert"
+	"github.com/kdeconinck/nunit"
+	"github.com/kdeconinck/xunit"
+)
+
+// UT: Load an XML file containing a .NET test result in NUnit's v3 XML format.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		xmlData string
+		want    xunit.TestRun
+		wantErr bool
+	}{
+		{
+			xmlData: "<assemblies />",
+			wantErr: true,
+		},
+		{
+			xmlData: "<test-run id=\"0\" start-time=\"2023-10-07 18:53:19Z\" end-time=\"2023-10-07 18:53:21Z\" duration=\"2.0\">\n" +
+				"  <test-suite type=\"Assembly\" name=\"App.Tests.dll\" fullname=\"C:\\src\\App.Tests.dll\" start-time=\"2023-10-07 18:53:19Z\" duration=\"1.75\">\n" +
+				"    <environment machine-name=\"WIN11\" user=\"Kevin\" user-domain=\"DOMAIN\" />\n" +
+				"    <test-suite type=\"TestSuite\" name=\"NS\" fullname=\"NS\">\n" +
+				"      <test-suite type=\"TestFixture\" name=\"CalculatorTests\" fullname=\"NS.CalculatorTests\">\n" +
+				"        <properties><property name=\"Category\" value=\"Unit\" /></properties>\n" +
+				"        <test-case name=\"DividesByZero\" fullname=\"NS.CalculatorTests.DividesByZero\" result=\"Failed\" label=\"Error\" duration=\"1.5\">\n" +
+				"          <properties><property name=\"Category\" value=\"Unit\" /><property name=\"Category\" value=\"Math\" /></properties>\n" +
+				"          <failure>\n" +
+				"            <message><![CDATA[System.DivideByZeroException : Attempted to divide by zero.]]></message>\n" +
+				"            <stack-trace><![CDATA[   at NS.CalculatorTests.DividesByZero()]]></stack-trace>\n" +
+				"          </failure>\n" +
+				"          <output><![CDATA[Dividing numbers.\n]]></output>\n" +
+				"        </test-case>\n" +
+				"        <test-suite type=\"ParameterizedMethod\" name=\"ReturnsSum\" fullname=\"NS.CalculatorTests.ReturnsSum\">\n" +
+				"          <test-case name=\"ReturnsSum(1,2)\" fullname=\"NS.CalculatorTests.ReturnsSum(1,2)\" result=\"Passed\" duration=\"0.25\" />\n" +
+				"        </test-suite>\n" +
+				"      </test-suite>\n" +
+				"      <test-suite type=\"TestFixture\" name=\"CalculatorTests+Add\" fullname=\"NS.CalculatorTests+Add\">\n" +
+				"        <test-case name=\"IsSkipped\" fullname=\"NS.CalculatorTests+Add.IsSkipped\" result=\"Skipped\" label=\"Ignored\" duration=\"0\">\n" +
+				"          <reason><message><![CDATA[Not implemented yet.]]></message></reason>\n" +
+				"        </test-case>\n" +
+				"        <test-case name=\"Is inconclusive\" fullname=\"NS.CalculatorTests+Add.IsInconclusive\" result=\"Inconclusive\" duration=\"0.1\" />\n" +
+				"      </test-suite>\n" +
+				"    </test-suite>\n" +
+				"  </test-suite>\n" +
+				"</test-run>",
+			want: xunit.TestRun{
+				Computer:     "WIN11",
+				User:         "DOMAIN\\Kevin",
+				StartTimeRTF: "2023-10-07 18:53:19Z",
+				EndTimeRTF:   "2023-10-07 18:53:21Z",
+				StartTime:    time.Date(2023, 10, 7, 18, 53, 19, 0, time.UTC),
+				EndTime:      time.Date(2023, 10, 7, 18, 53, 21, 0, time.UTC),
+				Assemblies: []xunit.Assembly{
+					{
+						Name:         "App.Tests.dll",
+						PassedCount:  1,
+						FailedCount:  1,
+						SkippedCount: 1,
+						NotRunCount:  1,
+						TotalCount:   4,
+						RunDate:      "2023-10-07",
+						RunTime:      "18:53:19Z",
+						Time:         1.75,
+						RunStart:     time.Date(2023, 10, 7, 18, 53, 19, 0, time.UTC),
+						Duration:     1750 * time.Millisecond,
+						TestGroups: []*xunit.TestGroup{
+							{
+								Name:  "",
+								Tests: []xunit.TestCase{},
+								Groups: []*xunit.TestGroup{
+									{
+										Name: "Calculator tests",
+										Groups: []*xunit.TestGroup{
+											{
+												Name: "Add",
+												Tests: []xunit.TestCase{
+													{
+														Name:     "Is skipped",
+														FullName: "NS.CalculatorTests+Add.IsSkipped",
+														Result:   xunit.Skip,
+														Reason:   "Not implemented yet.",
+														Groups:   []string{"Calculator tests", "Add"},
+													},
+													{
+														Name:     "Is inconclusive",
+														FullName: "NS.CalculatorTests+Add.IsInconclusive",
+														Result:   xunit.NotRun,
+														Time:     0.1,
+														Duration: 100 * time.Millisecond,
+														Groups:   []string{"Calculator tests", "Add"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							{
+								Name:  "Category - Math",
+								Tests: []xunit.TestCase{},
+								Groups: []*xunit.TestGroup{
+									{
+										Name: "Calculator tests",
+										Tests: []xunit.TestCase{
+											{
+												Name:     "Divides by zero",
+												FullName: "NS.CalculatorTests.DividesByZero",
+												Result:   xunit.Fail,
+												Time:     1.5,
+												Duration: 1500 * time.Millisecond,
+												Failure: &xunit.Failure{
+													ExceptionType: "System.DivideByZeroException",
+													Message:       "Attempted to divide by zero.",
+													StackTrace:    "   at NS.CalculatorTests.DividesByZero()",
+												},
+												Output: "Dividing numbers.",
+												Groups: []string{"Calculator tests"},
+												Traits: []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Category", Value: "Math"}},
+											},
+										},
+									},
+								},
+							},
+							{
+								Name:  "Category - Unit",
+								Tests: []xunit.TestCase{},
+								Groups: []*xunit.TestGroup{
+									{
+										Name: "Calculator tests",
+										Tests: []xunit.TestCase{
+											{
+												Name:     "Divides by zero",
+												FullName: "NS.CalculatorTests.DividesByZero",
+												Result:   xunit.Fail,
+												Time:     1.5,
+												Duration: 1500 * time.Millisecond,
+												Failure: &xunit.Failure{
+													ExceptionType: "System.DivideByZeroException",
+													Message:       "Attempted to divide by zero.",
+													StackTrace:    "   at NS.CalculatorTests.DividesByZero()",
+												},
+												Output: "Dividing numbers.",
+												Groups: []string{"Calculator tests"},
+												Traits: []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Category", Value: "Math"}},
+											},
+										},
+										Groups: []*xunit.TestGroup{
+											{
+												Name: "Returns sum",
+												Tests: []xunit.TestCase{
+													{
+														Name:     "Returns sum (1,2)",
+														FullName: "NS.CalculatorTests.ReturnsSum(1,2)",
+														Result:   xunit.Pass,
+														Time:     0.25,
+														Duration: 250 * time.Millisecond,
+														Groups:   []string{"Calculator tests", "Returns sum"},
+														Traits:   []xunit.Trait{{Name: "Category", Value: "Unit"}},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	} {
+		// HELPER FUNCTIONS.
+		fmtValue := func(v xunit.TestRun) string {
+			b, _ := json.MarshalIndent(v, "", "  ")
+			res := strings.Replace(string(b), "\n", "\n            ", -1)
+
+			return res
+		}
+
+		fmtXml := func(v string) string {
+			v = strings.Replace(v, "\n", "\n            ", -1)
+
+			return v
+		}
+
+		// ARRANGE.
+		rdr := strings.NewReader(tc.xmlData)
+
+		// ACT.
+		got, err := nunit.Load(rdr)
+
+		// ASSERT.
+		if tc.wantErr {
+			assert.NotNil(t, err, "", "\n\n"+
+				"UT Name:    Parse an invalid XML file containing a .NET test result in NUnit's v3 XML format.\n"+
+				"XML Input:  %s\n"+
+				"\033[32mExpected:   Error, NOT <nil>\033[0m\n"+
+				"\033[31mActual:     Error, %v\033[0m\n\n", fmtXml(tc.xmlData), err)
+		}
+
+		if !tc.wantErr {
+			assert.Nil(t, err, "", "\n\n"+
+				"UT Name:    Parse a valid XML file containing a .NET test result in NUnit's v3 XML format.\n"+
+				"XML Input:  %s\n"+
+				"\033[32mExpected:   Error, <nil>\033[0m\n"+
+				"\033[31mActual:     Error, %v\033[0m\n\n", fmtXml(tc.xmlData), err)
+		}
+
+		assert.EqualFn(t, got, tc.want, func(got xunit.TestRun, want xunit.TestRun) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Parse an XML file containing a .NET test result in NUnit's v3 XML format.\n"+
+			"XML Input:  %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n",
+			fmtXml(tc.xmlData), fmtValue(tc.want), fmtValue(got))
+	}
+}
@@ -0,0 +1,250 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package nunit contains functions for parsing XML files containing .NET test result(s) in NUnit's v3 XML format.
+// More information regarding this format can be found @ https://docs.nunit.org/articles/nunit/technical-notes/usage/Test-Result-XML-Format.html.
+package nunit
+
+import (
+	"io"
+	"strings"
+
+	"github.com/kdeconinck/camelcase"
+	"github.com/kdeconinck/slices"
+	"github.com/kdeconinck/words"
+	"github.com/kdeconinck/xunit"
+)
+
+// The types of the test suites that are NOT converted into a group, since they don't correspond with a class or a
+// method (such as an assembly or a namespace).
+var transparentSuites = []string{"Project", "Assembly", "TestSuite", "SetUpFixture"}
+
+// Load returns a TestRun constructed from the data in rdr.
+// The nested test suites (fixtures, parameterized fixtures and methods, ...) of each test are converted into the
+// groups of the test, and the categories of each test (including the ones of the enclosing test suites) are converted
+// into traits named "Category".
+func Load(rdr io.Reader) (xunit.TestRun, error) {
+	data, err := unmarshal(rdr)
+
+	if err != nil {
+		return xunit.TestRun{}, err
+	}
+
+	return readTestRun(data), nil
+}
+
+// Read r into a TestRun.
+func readTestRun(r testRun) xunit.TestRun {
+	tRun := xunit.TestRun{
+		StartTimeRTF: r.StartTime,
+		EndTimeRTF:   r.EndTime,
+		Assemblies:   make([]xunit.Assembly, 0),
+	}
+
+	for _, suite := range assemblySuites(r.Suites) {
+		if tRun.Computer == "" {
+			tRun.Computer = suite.Environment.MachineName
+		}
+
+		if tRun.User == "" {
+			tRun.User = suite.Environment.user()
+		}
+
+		tRun.Assemblies = append(tRun.Assemblies, suite.toAssembly())
+	}
+
+	tRun.ParseTimes()
+
+	return tRun
+}
+
+// Returns the test suites of type "Assembly" in suites (or in any of their nested test suites).
+func assemblySuites(suites []testSuite) []*testSuite {
+	result := make([]*testSuite, 0)
+
+	for idx := range suites {
+		if suites[idx].Type == "Assembly" {
+			result = append(result, &suites[idx])
+		} else {
+			result = append(result, assemblySuites(suites[idx].Suites)...)
+		}
+	}
+
+	return result
+}
+
+// Returns the name of the user in env, including the domain of the user (if there is one).
+func (env environment) user() string {
+	if env.UserDomain == "" || env.User == "" {
+		return env.User
+	}
+
+	return env.UserDomain + "\\" + env.User
+}
+
+// Returns suite as an Assembly.
+// The counts are calculated from the tests, since the ones that are stored in NUnit's v3 XML format don't distinguish
+// between skipped tests and tests that weren't run.
+func (suite *testSuite) toAssembly() xunit.Assembly {
+	tests := suite.testCases(nil, nil)
+
+	assembly := xunit.Assembly{
+		Name:       suite.Name,
+		TotalCount: len(tests),
+		TestGroups: xunit.GroupTests(tests),
+	}
+
+	assembly.Duration, _ = xunit.ParseSeconds(suite.Duration)
+	assembly.Time = float32(assembly.Duration.Seconds())
+
+	if date, clock, ok := strings.Cut(strings.Replace(suite.StartTime, "T", " ", 1), " "); ok {
+		assembly.RunDate = date
+		assembly.RunTime = clock
+	}
+
+	for _, tc := range tests {
+		switch tc.Result {
+		case xunit.Pass:
+			assembly.PassedCount++
+		case xunit.Fail:
+			assembly.FailedCount++
+		case xunit.Skip:
+			assembly.SkippedCount++
+		case xunit.NotRun:
+			assembly.NotRunCount++
+		}
+	}
+
+	return assembly
+}
+
+// Returns the tests in suite (and in all of its nested test suites).
+// Each test belongs to groups (the groups of the enclosing test suites) and has the given categories (the categories
+// of the enclosing test suites).
+func (suite *testSuite) testCases(groups []string, categories []string) []xunit.TestCase {
+	categories = appendCategories(categories[:len(categories):len(categories)], suite.Properties)
+
+	if !slices.Contains(transparentSuites, suite.Type) {
+		groups = groups[:len(groups):len(groups)]
+
+		for _, name := range strings.Split(suite.Name, "+") {
+			groups = append(groups, friendlyName(name))
+		}
+	}
+
+	tests := make([]xunit.TestCase, 0, len(suite.Cases))
+
+	for idx := range suite.Cases {
+		tests = append(tests, suite.Cases[idx].toTestCase(groups, categories))
+	}
+
+	for idx := range suite.Suites {
+		tests = append(tests, suite.Suites[idx].testCases(groups, categories)...)
+	}
+
+	return tests
+}
+
+// Returns t as a TestCase, which belongs to groups and has the given categories (besides its own categories).
+func (t *testCase) toTestCase(groups []string, categories []string) xunit.TestCase {
+	tc := xunit.TestCase{
+		Name:     friendlyName(t.Name),
+		FullName: t.FullName,
+		Result:   parseResult(t.Result),
+		Output:   strings.TrimSpace(t.Output),
+		Groups:   groups,
+	}
+
+	tc.Duration, _ = xunit.ParseSeconds(t.Duration)
+	tc.Time = float32(tc.Duration.Seconds())
+
+	if tc.FullName == "" {
+		tc.FullName = t.Name
+	}
+
+	switch {
+	case tc.Result == xunit.Fail && (t.Failure.Message != "" || t.Failure.StackTrace != ""):
+		tc.Failure = &xunit.Failure{Message: strings.TrimSpace(t.Failure.Message), StackTrace: t.Failure.StackTrace}
+
+		// NUnit prefixes the message of an unexpected exception with the type of the exception.
+		if exType, msg, ok := strings.Cut(tc.Failure.Message, " : "); ok && t.Label == "Error" {
+			tc.Failure.ExceptionType = exType
+			tc.Failure.Message = msg
+		}
+	case tc.Result == xunit.Skip || tc.Result == xunit.NotRun:
+		tc.Reason = strings.TrimSpace(t.Reason.Message)
+	}
+
+	for _, category := range appendCategories(categories[:len(categories):len(categories)], t.Properties) {
+		tc.Traits = append(tc.Traits, xunit.Trait{Name: "Category", Value: category})
+	}
+
+	return tc
+}
+
+// Returns categories, extended with the value of each property in properties named "Category", which isn't part of
+// categories yet.
+func appendCategories(categories []string, properties []property) []string {
+	for _, prop := range properties {
+		if prop.Name == "Category" && !slices.Contains(categories, prop.Value) {
+			categories = append(categories, prop.Value)
+		}
+	}
+
+	return categories
+}
+
+// Returns name (the name of a test or a test suite) in human-readable format.
+// The arguments of a parameterized test (or fixture) are kept as is, and names containing a space are considered to
+// be a display name, which are returned as is.
+func friendlyName(name string) string {
+	fnName, args := name, ""
+
+	if idx := strings.IndexAny(name, "(<"); idx > 0 {
+		fnName, args = name[:idx], " "+name[idx:]
+	}
+
+	if strings.Contains(fnName, " ") {
+		return name
+	}
+
+	return words.ToSentence(camelcase.Split(fnName)) + args
+}
+
+// Returns the Result that corresponds with result (the value of the `result` attribute of a test case).
+func parseResult(result string) xunit.Result {
+	switch result {
+	case "Passed", "Warning":
+		return xunit.Pass
+	case "Failed":
+		return xunit.Fail
+	case "Skipped":
+		return xunit.Skip
+	case "Inconclusive":
+		return xunit.NotRun
+	default:
+		return xunit.Unknown
+	}
+}
@@ -0,0 +1,231 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kdeconinck/junit"
+	"github.com/kdeconinck/maps"
+	"github.com/kdeconinck/xunit"
+)
+
+// The names of the output formats.
+const (
+	formatText  = "text"     // A human-readable tree, meant for a terminal.
+	formatJUnit = "junit"    // JUnit's XML format, meant for CI systems.
+	formatJSON  = "json"     // A JSON report, meant for scripts and dashboards.
+	formatHTML  = "html"     // A self-contained HTML report, meant to be published as an artifact.
+	formatMD    = "markdown" // A Markdown summary, meant for GitHub's step summaries and for comments on a PR.
+	formatGH    = "github"   // Workflow commands, which annotate the code of failing tests in GitHub Actions.
+	formatAzure = "azure"    // Logging commands, which annotate the code of failing tests in Azure Pipelines.
+	formatXUnit = "xunit"    // xUnit's v2 XML format, meant for consolidating (sharded) test runs.
+)
+
+// The environment variable which contains the path of the file with the summary of the current GitHub Actions step.
+const stepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// The functions which write the test result(s) in sources to w, for each supported output format.
+var formats = map[string]func(w io.Writer, cfg configuration, sources []source) error{
+	formatText:  writeText,
+	formatJUnit: writeJUnit,
+	formatJSON:  writeJSON,
+	formatHTML:  writeHTML,
+	formatMD:    writeMarkdown,
+	formatGH:    writeGitHub,
+	formatAzure: writeAzure,
+	formatXUnit: writeXUnit,
+}
+
+// The flags that control the output of a command.
+type outputFlags struct {
+	fs            *flag.FlagSet // The set the flags are registered on.
+	format        *string       // The name of the output format.
+	out           *string       // The name of the file to write the output to, empty for the stdout stream.
+	markdownLimit *int          // The value of the `--markdown-limit` flag.
+	stepSummary   *bool         // Whether a Markdown summary is appended to GitHub's step summary.
+	pathPrefixes  stringList    // The value of the `--path-prefix` flag.
+}
+
+// Register the flags that control the output of a command on fs, where format is the default output format.
+func newOutputFlags(fs *flag.FlagSet, format string) *outputFlags {
+	of := &outputFlags{
+		fs: fs,
+		format: fs.String("format", format,
+			"the output `format`, one of: "+strings.Join(maps.Keys(formats), ", ")),
+		out: fs.String("out", "", "write the output to `file`, instead of to stdout"),
+		markdownLimit: fs.Int("markdown-limit", stdConfiguration.Output.MarkdownLimit,
+			"the maximum size of the Markdown output, in `bytes` (0 for no limit)"),
+		stepSummary: fs.Bool("step-summary", false,
+			"also append a Markdown summary to the file named by $"+stepSummaryEnv+" (GitHub Actions)"),
+	}
+
+	fs.Var(&of.pathPrefixes, "path-prefix",
+		"a `from=to` pair, which replaces the prefix of paths in annotations (repeatable, comma-separated)")
+
+	return of
+}
+
+// Write the test result(s) in sources in the format that's selected by of.
+// If the format is invalid, or if the output can't be written, a message is written to the stderr stream of a and the
+// exit code of the application is returned, together with false.
+func (a *app) write(of *outputFlags, cfg configuration, sources []source) (int, bool) {
+	write, ok := formats[*of.format]
+
+	if !ok {
+		fmt.Fprintf(a.stderr, "%s: invalid value '%s' for --format, expected one of: %s\n", a.failed(),
+			*of.format, strings.Join(maps.Keys(formats), ", "))
+
+		return exitUsage, false
+	}
+
+	if isFlagSet(of.fs, "markdown-limit") {
+		if cfg.Output.MarkdownLimit = *of.markdownLimit; cfg.Output.MarkdownLimit < 0 {
+			fmt.Fprintln(a.stderr, a.failed()+": the Markdown limit can't be negative")
+
+			return exitUsage, false
+		}
+	}
+
+	if isFlagSet(of.fs, "path-prefix") {
+		cfg = cfg.clone()
+
+		for _, v := range of.pathPrefixes {
+			from, to, ok := strings.Cut(v, "=")
+
+			if !ok || from == "" {
+				fmt.Fprintf(a.stderr, "%s: invalid value '%s' for --path-prefix, expected from=to\n", a.failed(), v)
+
+				return exitUsage, false
+			}
+
+			cfg.Output.PathPrefixes[from] = to
+		}
+	}
+
+	if err := a.writeOutput(*of.out, func(w io.Writer) error { return write(w, cfg, sources) }); err != nil {
+		fmt.Fprintf(a.stderr, "%s - %s\n", a.failed(), err.Error())
+
+		return exitOutput, false
+	}
+
+	if *of.stepSummary {
+		if err := a.appendStepSummary(cfg, sources); err != nil {
+			fmt.Fprintf(a.stderr, "%s - %s\n", a.failed(), err.Error())
+
+			return exitOutput, false
+		}
+	}
+
+	return exitOK, true
+}
+
+// Append a Markdown summary of the test result(s) in sources to GitHub's step summary.
+// Since the size of a step summary is limited, the limit of cfg is reduced by the size of the existing summary. If
+// the application doesn't run in GitHub Actions, a warning is written to the stderr stream of a.
+func (a *app) appendStepSummary(cfg configuration, sources []source) error {
+	path := a.getenv(stepSummaryEnv)
+
+	if path == "" {
+		fmt.Fprintf(a.stderr, "\033[1;33mWarning\033[0m: $%s isn't set, the step summary isn't written.\n", stepSummaryEnv)
+
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+
+	if err != nil {
+		return err
+	}
+
+	if info, err := f.Stat(); err == nil && cfg.Output.MarkdownLimit > 0 {
+		cfg.Output.MarkdownLimit = max(cfg.Output.MarkdownLimit-int(info.Size()), 1)
+	}
+
+	if err := writeMarkdown(f, cfg, sources); err != nil {
+		f.Close()
+
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return f.Close()
+}
+
+// Execute write with the file named out, or with the stdout stream of a if out is empty.
+func (a *app) writeOutput(out string, write func(w io.Writer) error) error {
+	if out == "" {
+		return write(a.stdout)
+	}
+
+	f, err := os.Create(out)
+
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+
+		return fmt.Errorf("%s: %w", out, err)
+	}
+
+	return f.Close()
+}
+
+// Write the test result(s) in sources to w, in JUnit's XML format.
+// All the assemblies of all the sources are written to a single document.
+func writeJUnit(w io.Writer, _ configuration, sources []source) error {
+	return junit.Write(w, combine(sources))
+}
+
+// Write the test result(s) in sources to w, in xUnit's v2 XML format.
+// The test runs of all the sources are merged into a single document.
+func writeXUnit(w io.Writer, _ configuration, sources []source) error {
+	return xunit.Write(w, mergeSources(sources).run)
+}
+
+// Returns a single TestRun which contains all the assemblies of the test run(s) in sources.
+// The information about the test run itself is taken from the first source.
+func combine(sources []source) xunit.TestRun {
+	tRun := xunit.TestRun{Assemblies: make([]xunit.Assembly, 0)}
+
+	for idx, src := range sources {
+		if idx == 0 {
+			tRun = src.run
+			tRun.Assemblies = append([]xunit.Assembly{}, src.run.Assemblies...)
+
+			continue
+		}
+
+		tRun.Assemblies = append(tRun.Assemblies, src.run.Assemblies...)
+	}
+
+	return tRun
+}
@@ -0,0 +1,511 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kdeconinck/slices"
+	"github.com/kdeconinck/xunit"
+)
+
+// A printer writes test result(s) in a human-readable format.
+type printer struct {
+	w   io.Writer     // The writer to write the output to.
+	cfg configuration // The configuration of the application.
+}
+
+// Execute the `render` command.
+// The filters only narrow the test(s) that are written, the exit code is based on all the test(s).
+func runRender(a *app, cmd *command, args []string) int {
+	fs := a.newFlagSet(cmd, "[flags] [file ...]")
+	cf := newCommonFlags(fs)
+	of := newOutputFlags(fs, formatText)
+	ff := newFilterFlags(fs)
+	inputs, cfg, code, ok := a.parseArgs(fs, cf, args)
+
+	if !ok {
+		return code
+	}
+
+	filter, ok := a.parseFilter(ff)
+
+	if !ok {
+		return exitUsage
+	}
+
+	sources, loadFailed := a.load(inputs)
+
+	if code, ok := a.write(of, cfg, filterSources(filter, sources)); !ok {
+		return code
+	}
+
+	return exitCode(cfg, sources, loadFailed)
+}
+
+// Write the test result(s) in sources to w, as a human-readable tree.
+func writeText(w io.Writer, cfg configuration, sources []source) error {
+	p := &printer{w: w, cfg: cfg}
+	p.printHeader()
+
+	for _, src := range sources {
+		p.printRun(src)
+
+		for _, assembly := range src.run.Assemblies {
+			p.printAssembly(assembly)
+
+			if cfg.Output.GroupBy == groupByCollection {
+				p.printCollections(assembly)
+			} else {
+				p.printTestGroups(assembly)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Execute the `summary` command.
+// The filters only narrow the test(s) that are counted, the exit code is based on all the test(s).
+func runSummary(a *app, cmd *command, args []string) int {
+	fs := a.newFlagSet(cmd, "[flags] [file ...]")
+	cf := newCommonFlags(fs)
+	ff := newFilterFlags(fs)
+	inputs, cfg, code, ok := a.parseArgs(fs, cf, args)
+
+	if !ok {
+		return code
+	}
+
+	filter, ok := a.parseFilter(ff)
+
+	if !ok {
+		return exitUsage
+	}
+
+	p := &printer{w: a.stdout, cfg: cfg}
+	p.printHeader()
+
+	sources, loadFailed := a.load(inputs)
+
+	for _, src := range filterSources(filter, sources) {
+		p.printRun(src)
+
+		for _, assembly := range src.run.Assemblies {
+			p.printAssembly(assembly)
+		}
+
+		fmt.Fprintln(p.w, "")
+	}
+
+	return exitCode(cfg, sources, loadFailed)
+}
+
+// Print the ASCII header, unless it's disabled in the configuration.
+func (p *printer) printHeader() {
+	if !p.cfg.Output.Header {
+		return
+	}
+
+	fmt.Fprintln(p.w, "    _  _ ___ _____   _____       _    __   ___              _ _            ")
+	fmt.Fprintln(p.w, "   | \\| | __|_   _| |_   _|__ __| |_  \\ \\ / (_)____  _ __ _| (_)______ _ _ ")
+	fmt.Fprintln(p.w, "  _| .` | _|  | |     | |/ -_|_-<  _|  \\ V /| (_-< || / _` | | |_ / -_) '_|")
+	fmt.Fprintln(p.w, " (_)_|\\_|___| |_|     |_|\\___/__/\\__|   \\_/ |_/__/\\_,_\\__,_|_|_/__\\___|_|  ")
+	fmt.Fprintln(p.w, "")
+}
+
+// Print the information about the test run in src.
+func (p *printer) printRun(src source) {
+	fmt.Fprintf(p.w, "Input source:         %s\r\n", src.name)
+	fmt.Fprintf(p.w, "Amount of assemblies: %v\r\n", len(src.run.Assemblies))
+
+	if src.run.Computer != "" {
+		fmt.Fprintf(p.w, "Computer:             %s\r\n", src.run.Computer)
+	}
+
+	if src.run.User != "" {
+		fmt.Fprintf(p.w, "User:                 %s\r\n", src.run.User)
+	}
+
+	if src.run.StartTimeRTF != "" {
+		fmt.Fprintf(p.w, "Start time:           %s\r\n", src.run.StartTimeRTF)
+	}
+
+	if src.run.EndTimeRTF != "" {
+		fmt.Fprintf(p.w, "End time:             %s\r\n", src.run.EndTimeRTF)
+	} else if src.run.Timestamp != "" {
+		fmt.Fprintf(p.w, "End time:             %s\r\n", src.run.Timestamp)
+	}
+}
+
+// Print the information about assembly.
+func (p *printer) printAssembly(assembly xunit.Assembly) {
+	fmt.Fprintln(p.w, "")
+	fmt.Fprintf(p.w, "  Assembly:         %s", assembly.Name)
+
+	if assembly.FailedCount != 0 {
+		fmt.Fprintf(p.w, " - %s\r\n", p.paint(p.cfg.Colors.Fail,
+			fmt.Sprintf("⛌ Failed (%v of %v failed).", assembly.FailedCount, assembly.TotalCount)))
+	} else {
+		fmt.Fprintf(p.w, " - %s \r\n", p.paint(p.cfg.Colors.Pass,
+			fmt.Sprintf("✓ Passed (%v of %v passed).", assembly.PassedCount, assembly.TotalCount)))
+	}
+
+	if assembly.SkippedCount != 0 {
+		fmt.Fprintf(p.w, "                    %s\r\n", p.paint(p.cfg.Colors.Skip,
+			fmt.Sprintf("⊘ Skipped (%v of %v skipped).", assembly.SkippedCount, assembly.TotalCount)))
+	}
+
+	if p.cfg.Output.Verbose {
+		p.printEnvironment(assembly)
+	}
+
+	fmt.Fprintf(p.w, "  Date / time:      %s %s\r\n", assembly.RunDate, assembly.RunTime)
+
+	if assembly.TimeRTF != "" {
+		fmt.Fprintf(p.w, "  Total time:       %v.\r\n", assembly.TimeRTF)
+	} else {
+		fmt.Fprintf(p.w, "  Total time:       %v seconds.\r\n", assembly.Time)
+	}
+
+	// Print information about the assembly.
+	fmt.Fprintln(p.w, "")
+	fmt.Fprintf(p.w, "  # tests:         %v\r\n", assembly.TotalCount)
+	fmt.Fprintf(p.w, "  # Passed tests:  %v\r\n", assembly.PassedCount)
+	fmt.Fprintf(p.w, "  # Failed tests:  %v\r\n", assembly.FailedCount)
+	fmt.Fprintf(p.w, "  # Skipped tests: %v\r\n", assembly.SkippedCount)
+	fmt.Fprintf(p.w, "  # Not run tests: %v\r\n", assembly.NotRunCount)
+	fmt.Fprintf(p.w, "  # Errors:        %v\r\n", assembly.ErrorCount)
+
+	p.printErrors(assembly)
+}
+
+// Print the environmental information of assembly, such as its target framework and the test framework that ran it.
+func (p *printer) printEnvironment(assembly xunit.Assembly) {
+	if env := environment(assembly); env != "" {
+		fmt.Fprintf(p.w, "  Environment:      %s\r\n", env)
+	}
+
+	if assembly.ConfigFile != "" {
+		fmt.Fprintf(p.w, "  Config file:      %s\r\n", assembly.ConfigFile)
+	}
+
+	if assembly.StartTimeRTF != "" {
+		fmt.Fprintf(p.w, "  Start time:       %s\r\n", assembly.StartTimeRTF)
+	}
+
+	if assembly.EndTimeRTF != "" {
+		fmt.Fprintf(p.w, "  End time:         %s\r\n", assembly.EndTimeRTF)
+	}
+}
+
+// Returns the target framework, the test framework and the environment of assembly, separated by a `·`.
+// The ones which aren't known are left out.
+func environment(assembly xunit.Assembly) string {
+	parts := make([]string, 0, 3)
+
+	for _, v := range []string{assembly.TargetFramework, assembly.TestFramework, assembly.Environment} {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// Print the environmental errors of assembly, such as failures while cleaning up a test collection.
+func (p *printer) printErrors(assembly xunit.Assembly) {
+	if len(assembly.Errors) == 0 {
+		return
+	}
+
+	fmt.Fprintln(p.w, "")
+	fmt.Fprintf(p.w, "  %s\r\n", p.paint(p.cfg.Colors.Fail, "Environment errors:"))
+
+	for _, e := range assembly.Errors {
+		fmt.Fprintf(p.w, "    %s %s\r\n", p.paint(p.cfg.Colors.Fail, "⛌"), errorName(e))
+
+		if e.Failure != nil {
+			p.printFailure(e.Failure, "      ")
+		}
+	}
+}
+
+// Print the tests of assembly, grouped by trait and nested class.
+func (p *printer) printTestGroups(assembly xunit.Assembly) {
+	fmt.Fprintln(p.w, "")
+
+	// Loop over all the groups in the assembly.
+	for _, tGroup := range assembly.TestGroups {
+		if tGroup.Name != "" {
+			fmt.Fprintln(p.w, "")
+			fmt.Fprintf(p.w, "  Trait: %s\r\n", tGroup.Name)
+		}
+
+		// Loop over all the test(s) in this group.
+		for _, tc := range tGroup.Tests {
+			suffix := ""
+
+			if tGroup.Name != "" {
+				suffix = "  "
+			}
+
+			p.printTest(tc, suffix+"  ")
+		}
+
+		// Loop over all the groups in this group.
+		for _, group := range tGroup.Groups {
+			fmt.Fprintln(p.w, "")
+			p.printGroup(group, "", p.printTest)
+		}
+	}
+}
+
+// Print the tests of assembly, grouped by the test collection they ran in and nested class.
+// Each collection is printed with its number of tests and the time it took to run, including its share of the time of
+// the assembly, since the slowest collection limits how fast the tests of the assembly can run in parallel.
+func (p *printer) printCollections(assembly xunit.Assembly) {
+	fmt.Fprintln(p.w, "")
+
+	collections, tests := groupCollections(assembly)
+
+	for _, collection := range collections {
+		fmt.Fprintln(p.w, "")
+		p.printCollection(assembly, collection, tests[collection.Name])
+		p.printTree(xunit.GroupTests(tests[collection.Name]), "", p.printTest)
+	}
+}
+
+// Print the name of collection (of assembly), the number of tests in it and the time it took to run.
+func (p *printer) printCollection(assembly xunit.Assembly, collection xunit.Collection, tests []xunit.TestCase) {
+	name, seconds, duration := collection.Name, collection.Time, collection.Duration
+
+	if name == "" {
+		name = "Without collection"
+	}
+
+	if duration == 0 {
+		for _, tc := range tests {
+			seconds += tc.Time
+			duration += tc.Duration
+		}
+	}
+
+	fmt.Fprintf(p.w, "  Collection: %s - %v test(s), %v seconds", name, len(tests), seconds)
+
+	if assembly.Duration > 0 && duration > 0 {
+		fmt.Fprintf(p.w, " (%.0f%% of the assembly)", 100*duration.Seconds()/assembly.Duration.Seconds())
+	}
+
+	fmt.Fprint(p.w, ".\r\n")
+}
+
+// Returns the collections of assembly, in the order they were run, together with their tests (by name).
+// Only the collections with tests are returned. The tests are returned without their traits, since they're grouped by
+// collection instead. Tests that belong to a collection which isn't part of assembly are returned in a collection
+// with only a name.
+func groupCollections(assembly xunit.Assembly) ([]xunit.Collection, map[string][]xunit.TestCase) {
+	collections := make([]xunit.Collection, 0, len(assembly.Collections))
+	tests := make(map[string][]xunit.TestCase)
+
+	for _, tc := range assembly.Tests() {
+		tc.Traits = nil
+		tests[tc.Collection] = append(tests[tc.Collection], tc)
+	}
+
+	for _, collection := range assembly.Collections {
+		if _, ok := tests[collection.Name]; ok && !containsCollection(collections, collection.Name) {
+			collections = append(collections, collection)
+		}
+	}
+
+	for _, tc := range assembly.Tests() {
+		if !containsCollection(collections, tc.Collection) {
+			collections = append(collections, xunit.Collection{Name: tc.Collection})
+		}
+	}
+
+	return collections, tests
+}
+
+// Returns true if collections contains a collection named name, false otherwise.
+func containsCollection(collections []xunit.Collection, name string) bool {
+	return slices.ContainsFn(collections, xunit.Collection{Name: name}, func(c, want xunit.Collection) bool {
+		return c.Name == want.Name
+	})
+}
+
+// Print tGroups (tests which aren't grouped by trait) as a tree of nested classes, prefixed with indent.
+// Each test is printed using printTest.
+func (p *printer) printTree(tGroups []*xunit.TestGroup, indent string, printTest func(xunit.TestCase, string)) {
+	for _, tGroup := range tGroups {
+		for _, tc := range tGroup.Tests {
+			printTest(tc, indent+"    ")
+		}
+
+		for _, group := range tGroup.Groups {
+			fmt.Fprintln(p.w, "")
+			p.printGroup(group, indent+"  ", printTest)
+		}
+	}
+}
+
+// Print group, and all of its subgroups, prefixed with indent.
+// Each test is printed using printTest.
+func (p *printer) printGroup(group *xunit.TestGroup, indent string, printTest func(xunit.TestCase, string)) {
+	fmt.Fprintf(p.w, "%s  %s\r\n", indent, group.Name)
+
+	// Loop over all the test(s) in this group.
+	for _, tc := range group.Tests {
+		printTest(tc, indent+"     ")
+	}
+
+	if len(group.Tests) > 0 {
+		fmt.Fprintln(p.w, "")
+	}
+
+	for _, group = range group.Groups {
+		p.printGroup(group, indent+"  ", printTest)
+	}
+}
+
+// Print a single test, prefixed with indent.
+// When the test failed, the failure is printed underneath it, when it was skipped, the reason is printed. The
+// warnings of the test are printed as well, and so is its output, depending on the configuration.
+func (p *printer) printTest(tc xunit.TestCase, indent string) {
+	fmt.Fprintf(p.w, "%s%s %s %s (%v seconds)\r\n", indent, p.speed(tc.Time), p.status(tc.Result), tc.Name, tc.Time)
+
+	if tc.Failure != nil {
+		p.printFailure(tc.Failure, indent+"     ")
+	}
+
+	if tc.Result == xunit.Skip && tc.Reason != "" {
+		fmt.Fprintf(p.w, "%s     %s %s\r\n", indent, p.paint(p.cfg.Colors.Skip, "Reason:"), tc.Reason)
+	}
+
+	for _, warning := range tc.Warnings {
+		fmt.Fprintf(p.w, "%s     %s %s\r\n", indent, p.paint(p.cfg.Colors.Skip, "Warning:"), strings.TrimSpace(warning))
+	}
+
+	if p.cfg.showsOutput(tc) {
+		p.printOutput(tc.Output, indent+"     ")
+	}
+}
+
+// Print output (the output that was captured while running a test), prefixed with indent.
+// When output has more lines than the configuration allows, the remaining lines are replaced by a notice.
+func (p *printer) printOutput(output, indent string) {
+	lines := strings.Split(strings.TrimRight(output, "\r\n"), "\n")
+	limit := len(lines)
+
+	if p.cfg.Output.OutputLines > 0 {
+		limit = min(limit, p.cfg.Output.OutputLines)
+	}
+
+	fmt.Fprintf(p.w, "%s%s\r\n", indent, p.paint(p.cfg.Colors.Muted, "Output:"))
+
+	for _, line := range lines[:limit] {
+		fmt.Fprintf(p.w, "%s  %s\r\n", indent, strings.TrimRight(line, "\r"))
+	}
+
+	if omitted := len(lines) - limit; omitted > 0 {
+		fmt.Fprintf(p.w, "%s  %s\r\n", indent, p.paint(p.cfg.Colors.Muted,
+			fmt.Sprintf("... %v more line(s), the full output is available in the JSON and HTML reports.", omitted)))
+	}
+}
+
+// Print the exception type, the message and the stack trace of failure.
+// Each line is prefixed with indent, the lines of the stack trace are indented a bit further.
+func (p *printer) printFailure(failure *xunit.Failure, indent string) {
+	if failure.ExceptionType != "" {
+		fmt.Fprintf(p.w, "%s%s\r\n", indent, p.paint(p.cfg.Colors.Fail, failure.ExceptionType))
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(failure.Message), "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			fmt.Fprintf(p.w, "%s%s\r\n", indent, line)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(failure.StackTrace), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			fmt.Fprintf(p.w, "%s  %s\r\n", indent, p.paint(p.cfg.Colors.Muted, line))
+		}
+	}
+}
+
+// Returns the name of e, which consists of where the error happened and, if it's known, the name of the element
+// (for example, the test collection) where it happened.
+func errorName(e xunit.EnvironmentError) string {
+	switch {
+	case e.Type == "":
+		return e.Name
+	case e.Name == "":
+		return e.Type
+	default:
+		return e.Type + ": " + e.Name
+	}
+}
+
+// Returns the symbol that represents the speed of a test that took t seconds to run.
+func (p *printer) speed(t float32) string {
+	switch p.cfg.speed(t) {
+	case speedFast:
+		return "🚀"
+	case speedNormal:
+		return "🕐"
+	default:
+		return "🐌"
+	}
+}
+
+// Returns the (colored) symbol that represents result.
+func (p *printer) status(result xunit.Result) string {
+	switch result {
+	case xunit.Pass:
+		return p.paint(p.cfg.Colors.Pass, "✓")
+	case xunit.Fail:
+		return p.paint(p.cfg.Colors.Fail, "⛌")
+	case xunit.Skip:
+		return p.paint(p.cfg.Colors.Skip, "⊘")
+	case xunit.NotRun:
+		return p.paint(p.cfg.Colors.NotRun, "○")
+	default:
+		return p.paint(p.cfg.Colors.Unknown, "?")
+	}
+}
+
+// Returns text, wrapped in the ANSI escape sequences for color.
+// If colors are disabled, or if color is empty, text is returned as is.
+func (p *printer) paint(color, text string) string {
+	if !p.cfg.Colors.Enabled || color == "" {
+		return text
+	}
+
+	return "\033[" + color + "m" + text + "\033[0m"
+}
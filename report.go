@@ -0,0 +1,272 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/kdeconinck/xunit"
+)
+
+// The version of the JSON report, which is described by the JSON schema in `schema/report.v1.json`.
+// Adding a field is a compatible change, which doesn't change the version. Removing a field, or changing the meaning
+// of a field, requires a new version (and a new schema).
+const reportSchemaVersion = 1
+
+// A jsonReport is the top-level object of the JSON report.
+type jsonReport struct {
+	SchemaVersion int       `json:"schemaVersion"` // The version of the schema of the report.
+	Runs          []jsonRun `json:"runs"`          // The test run of each input file.
+}
+
+// A jsonRun is a test run, together with the name of the file it was loaded from.
+type jsonRun struct {
+	Source     string         `json:"source"`              // The name of the file the test run was loaded from.
+	Computer   string         `json:"computer,omitempty"`  // The name of the computer that ran the tests.
+	User       string         `json:"user,omitempty"`      // The name of the user that ran the tests.
+	StartTime  string         `json:"startTime,omitempty"` // The time the first assembly started running.
+	EndTime    string         `json:"endTime,omitempty"`   // The time the last assembly finished running.
+	Timestamp  string         `json:"timestamp,omitempty"` // The time the first assembly started running.
+	Assemblies []jsonAssembly `json:"assemblies"`          // The assemblies of the test run.
+}
+
+// A jsonAssembly is the run of a single test assembly.
+type jsonAssembly struct {
+	ID              string      `json:"id,omitempty"`              // The unique ID of the assembly within the run.
+	Name            string      `json:"name"`                      // The name of the assembly.
+	ConfigFile      string      `json:"configFile,omitempty"`      // The configuration file used to run the assembly.
+	Environment     string      `json:"environment,omitempty"`     // The environment the assembly ran in.
+	TargetFramework string      `json:"targetFramework,omitempty"` // The target framework the assembly was built for.
+	TestFramework   string      `json:"testFramework,omitempty"`   // The test framework that ran the assembly.
+	StartTime       string      `json:"startTime,omitempty"`       // The time the assembly started running.
+	EndTime         string      `json:"endTime,omitempty"`         // The time the assembly finished running.
+	RunDate         string      `json:"runDate,omitempty"`         // The date when the assembly started running.
+	RunTime         string      `json:"runTime,omitempty"`         // The time when the assembly started running.
+	Time            float32     `json:"time"`                      // The number of seconds that the assembly took to run.
+	Counts          jsonCounts  `json:"counts"`                    // The number of tests, per result.
+	TraitGroups     []jsonGroup `json:"traitGroups"`               // The tests, grouped per trait (unnamed for none).
+	Errors          []jsonError `json:"errors,omitempty"`          // The environmental errors experienced in the assembly.
+}
+
+// A jsonError is an environmental error, which happened outside the scope of running a single test.
+type jsonError struct {
+	Type    string       `json:"type,omitempty"`    // Where the error happened, such as "test-collection-cleanup".
+	Name    string       `json:"name,omitempty"`    // The name of the element where the error happened.
+	Failure *jsonFailure `json:"failure,omitempty"` // The exception that caused the error.
+}
+
+// A jsonCounts contains the number of tests of an assembly, per result.
+type jsonCounts struct {
+	Total   int `json:"total"`   // The total number of tests.
+	Passed  int `json:"passed"`  // The number of tests that passed.
+	Failed  int `json:"failed"`  // The number of tests that failed.
+	Skipped int `json:"skipped"` // The number of tests that were skipped.
+	NotRun  int `json:"notRun"`  // The number of tests that weren't run.
+	Errors  int `json:"errors"`  // The number of environmental errors.
+}
+
+// A jsonGroup is a (nested) group of tests, such as a trait or a (nested) class.
+type jsonGroup struct {
+	Name   string      `json:"name"`   // The human-readable name of the group.
+	Tests  []jsonTest  `json:"tests"`  // The tests that belong directly to the group.
+	Groups []jsonGroup `json:"groups"` // The subgroups of the group.
+}
+
+// A jsonTest is the result of a single test.
+type jsonTest struct {
+	Name       string       `json:"name"`                 // The human-readable name of the test.
+	FullName   string       `json:"fullName,omitempty"`   // The fully qualified name of the test.
+	Path       []string     `json:"path"`                 // The human-readable names of the (nested) groups of the test.
+	Result     string       `json:"result"`               // One of "pass", "fail", "skip", "notRun" or "unknown".
+	Time       float32      `json:"time"`                 // The number of seconds that the test took to run.
+	Duration   float64      `json:"duration"`             // The number of seconds that the test took, at full precision.
+	Speed      string       `json:"speed"`                // One of "fast", "normal" or "slow", based on the thresholds.
+	Collection string       `json:"collection,omitempty"` // The name of the test collection the test ran in.
+	SourceFile string       `json:"sourceFile,omitempty"` // The path of the file which contains the test.
+	SourceLine int          `json:"sourceLine,omitempty"` // The line (in the source file) on which the test is declared.
+	Reason     string       `json:"reason,omitempty"`     // The reason why the test was skipped.
+	Failure    *jsonFailure `json:"failure,omitempty"`    // The reason why the test failed.
+	Output     string       `json:"output,omitempty"`     // The output that was captured while running the test.
+	Warnings   []string     `json:"warnings,omitempty"`   // The warnings that were reported while running the test.
+	Traits     []jsonTrait  `json:"traits"`               // The traits of the test.
+}
+
+// A jsonFailure is the reason why a test failed, or why an environmental error happened.
+type jsonFailure struct {
+	ExceptionType string `json:"exceptionType,omitempty"` // The type of the exception that was thrown.
+	Message       string `json:"message,omitempty"`       // The message of the exception.
+	StackTrace    string `json:"stackTrace,omitempty"`    // The stack trace of the exception.
+}
+
+// A jsonTrait is a single trait name/value pair.
+type jsonTrait struct {
+	Name  string `json:"name"`  // The name of the trait.
+	Value string `json:"value"` // The value of the trait.
+}
+
+// Write the test result(s) in sources to w, as a JSON report.
+func writeJSON(w io.Writer, cfg configuration, sources []source) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(newJSONReport(cfg, sources))
+}
+
+// Returns the test result(s) in sources as a jsonReport.
+func newJSONReport(cfg configuration, sources []source) jsonReport {
+	report := jsonReport{SchemaVersion: reportSchemaVersion, Runs: make([]jsonRun, 0, len(sources))}
+
+	for _, src := range sources {
+		report.Runs = append(report.Runs, newJSONRun(cfg, src))
+	}
+
+	return report
+}
+
+// Returns the test run in src as a jsonRun.
+func newJSONRun(cfg configuration, src source) jsonRun {
+	run := jsonRun{
+		Source:     src.name,
+		Computer:   src.run.Computer,
+		User:       src.run.User,
+		StartTime:  src.run.StartTimeRTF,
+		EndTime:    src.run.EndTimeRTF,
+		Timestamp:  src.run.Timestamp,
+		Assemblies: make([]jsonAssembly, 0, len(src.run.Assemblies)),
+	}
+
+	for _, assembly := range src.run.Assemblies {
+		run.Assemblies = append(run.Assemblies, jsonAssembly{
+			ID:              assembly.ID,
+			Name:            assembly.Name,
+			ConfigFile:      assembly.ConfigFile,
+			Environment:     assembly.Environment,
+			TargetFramework: assembly.TargetFramework,
+			TestFramework:   assembly.TestFramework,
+			StartTime:       assembly.StartTimeRTF,
+			EndTime:         assembly.EndTimeRTF,
+			RunDate:         assembly.RunDate,
+			RunTime:         assembly.RunTime,
+			Time:            assembly.Time,
+			Counts: jsonCounts{
+				Total:   assembly.TotalCount,
+				Passed:  assembly.PassedCount,
+				Failed:  assembly.FailedCount,
+				Skipped: assembly.SkippedCount,
+				NotRun:  assembly.NotRunCount,
+				Errors:  assembly.ErrorCount,
+			},
+			TraitGroups: newJSONGroups(cfg, assembly.TestGroups),
+			Errors:      newJSONErrors(assembly.Errors),
+		})
+	}
+
+	return run
+}
+
+// Returns errors as a list of jsonError.
+func newJSONErrors(errors []xunit.EnvironmentError) []jsonError {
+	result := make([]jsonError, 0, len(errors))
+
+	for _, e := range errors {
+		result = append(result, jsonError{Type: e.Type, Name: e.Name, Failure: newJSONFailure(e.Failure)})
+	}
+
+	return result
+}
+
+// Returns f as a jsonFailure, or <nil> if f is <nil>.
+func newJSONFailure(f *xunit.Failure) *jsonFailure {
+	if f == nil {
+		return nil
+	}
+
+	return &jsonFailure{ExceptionType: f.ExceptionType, Message: f.Message, StackTrace: f.StackTrace}
+}
+
+// Returns groups as a list of jsonGroup.
+func newJSONGroups(cfg configuration, groups []*xunit.TestGroup) []jsonGroup {
+	result := make([]jsonGroup, 0, len(groups))
+
+	for _, group := range groups {
+		jGroup := jsonGroup{Name: group.Name, Tests: make([]jsonTest, 0, len(group.Tests))}
+
+		for _, tc := range group.Tests {
+			jGroup.Tests = append(jGroup.Tests, newJSONTest(cfg, tc))
+		}
+
+		jGroup.Groups = newJSONGroups(cfg, group.Groups)
+		result = append(result, jGroup)
+	}
+
+	return result
+}
+
+// Returns tc as a jsonTest.
+func newJSONTest(cfg configuration, tc xunit.TestCase) jsonTest {
+	t := jsonTest{
+		Name:       tc.Name,
+		FullName:   tc.FullName,
+		Path:       append(make([]string, 0, len(tc.Groups)), tc.Groups...),
+		Result:     resultName(tc.Result),
+		Time:       tc.Time,
+		Duration:   tc.Duration.Seconds(),
+		Speed:      cfg.speed(tc.Time),
+		Collection: tc.Collection,
+		SourceFile: tc.SourceFile,
+		SourceLine: tc.SourceLine,
+		Reason:     tc.Reason,
+		Output:     tc.Output,
+		Warnings:   tc.Warnings,
+		Traits:     make([]jsonTrait, 0, len(tc.Traits)),
+	}
+
+	t.Failure = newJSONFailure(tc.Failure)
+
+	for _, trait := range tc.Traits {
+		t.Traits = append(t.Traits, jsonTrait{Name: trait.Name, Value: trait.Value})
+	}
+
+	return t
+}
+
+// Returns the name of result, as it's written in the JSON report.
+// The names are part of the schema of the report, so they don't depend on the names that are used by the library.
+func resultName(result xunit.Result) string {
+	switch result {
+	case xunit.Pass:
+		return "pass"
+	case xunit.Fail:
+		return "fail"
+	case xunit.Skip:
+		return "skip"
+	case xunit.NotRun:
+		return "notRun"
+	default:
+		return "unknown"
+	}
+}
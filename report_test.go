@@ -0,0 +1,163 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify that the JSON report matches its schema.
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/assert"
+	"github.com/kdeconinck/xunit"
+)
+
+// UT: Verify that each field of the JSON report is described by its schema.
+func TestReportSchema(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	var schema struct {
+		Properties map[string]struct {
+			Const int `json:"const"`
+		} `json:"properties"`
+		Defs map[string]struct {
+			Properties map[string]any `json:"properties"`
+		} `json:"$defs"`
+	}
+
+	data, err := os.ReadFile("schema/report.v1.json")
+
+	if err == nil {
+		err = json.Unmarshal(data, &schema)
+	}
+
+	// ASSERT.
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Verify that each field of the JSON report is described by its schema.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+
+	assert.Equal(t, schema.Properties["schemaVersion"].Const, reportSchemaVersion, "", "\n\n"+
+		"UT Name:    Verify that each field of the JSON report is described by its schema.\n"+
+		"\033[32mExpected:   Schema version %v\033[0m\n"+
+		"\033[31mActual:     Schema version %v\033[0m\n\n", reportSchemaVersion, schema.Properties["schemaVersion"].Const)
+
+	for def, v := range map[string]any{
+		"run":      jsonRun{},
+		"assembly": jsonAssembly{},
+		"counts":   jsonCounts{},
+		"group":    jsonGroup{},
+		"test":     jsonTest{},
+		"failure":  jsonFailure{},
+		"trait":    jsonTrait{},
+	} {
+		rt := reflect.TypeOf(v)
+
+		for idx := 0; idx < rt.NumField(); idx++ {
+			name, _, _ := strings.Cut(rt.Field(idx).Tag.Get("json"), ",")
+			_, ok := schema.Defs[def].Properties[name]
+
+			assert.Equal(t, ok, true, "", "\n\n"+
+				"UT Name:    Verify that each field of the JSON report is described by its schema.\n"+
+				"\033[32mExpected:   Property '%s' in '#/$defs/%s'\033[0m\n"+
+				"\033[31mActual:     No such property\033[0m\n\n", name, def)
+		}
+	}
+}
+
+// UT: Write a JSON report, and read it again.
+func TestWriteJSON(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	tc := xunit.NewTestCase("NS.TestClass.Throws")
+	tc.Result = xunit.Fail
+	tc.Time = 1.5
+	tc.Duration = 1500 * time.Millisecond
+	tc.Collection = "Test collection for NS.TestClass"
+	tc.SourceFile = "/src/TestClass.cs"
+	tc.SourceLine = 12
+	tc.Failure = &xunit.Failure{ExceptionType: "System.Exception", Message: "Boom."}
+	tc.Warnings = []string{"Deprecated API."}
+	tc.Traits = []xunit.Trait{{Name: "Category", Value: "Unit"}}
+
+	sources := []source{
+		{
+			name: "App.xml",
+			run: xunit.TestRun{
+				Assemblies: []xunit.Assembly{
+					{
+						Name: "App.dll", FailedCount: 1, ErrorCount: 1, TotalCount: 1,
+						TestGroups: xunit.GroupTests([]xunit.TestCase{tc}),
+						Errors:     []xunit.EnvironmentError{{Type: "fatal", Name: "App.dll"}},
+					},
+				},
+			},
+		},
+	}
+
+	want := newJSONReport(stdConfiguration, sources)
+
+	// ACT.
+	var (
+		b   strings.Builder
+		got jsonReport
+	)
+
+	err := writeJSON(&b, stdConfiguration, sources)
+
+	if err == nil {
+		err = json.Unmarshal([]byte(b.String()), &got)
+	}
+
+	// ASSERT.
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Write a JSON report, and read it again.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+
+	assert.EqualFn(t, got, want, func(got jsonReport, want jsonReport) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Write a JSON report, and read it again.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+
+	jTest := got.Runs[0].Assemblies[0].TraitGroups[0].Tests[0]
+	gotFields := []any{jTest.FullName, jTest.Duration, jTest.Collection, jTest.SourceFile, jTest.SourceLine}
+	wantFields := []any{tc.FullName, 1.5, tc.Collection, tc.SourceFile, tc.SourceLine}
+
+	assert.EqualFn(t, gotFields, wantFields, func(got []any, want []any) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Write a JSON report, and read it again.\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", wantFields, gotFields)
+}
@@ -0,0 +1,113 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kdeconinck/xunit"
+)
+
+// Execute the `stats` command.
+func runStats(a *app, cmd *command, args []string) int {
+	fs := a.newFlagSet(cmd, "[flags] [file ...]")
+	cf := newCommonFlags(fs)
+	top := fs.Int("top", stdConfiguration.Output.Top, "the `number` of slowest tests to print per assembly")
+	inputs, cfg, code, ok := a.parseArgs(fs, cf, args)
+
+	if !ok {
+		return code
+	}
+
+	if !isFlagSet(fs, "top") {
+		*top = cfg.Output.Top
+	} else if *top < 0 {
+		fmt.Fprintf(a.stderr, "%s: invalid value '%v' for --top, expected a number that isn't negative\n",
+			a.failed(), *top)
+
+		return exitUsage
+	}
+
+	p := &printer{w: a.stdout, cfg: cfg}
+	p.printHeader()
+
+	sources, loadFailed := a.load(inputs)
+
+	for _, src := range sources {
+		fmt.Fprintf(p.w, "Input source:         %s\r\n", src.name)
+
+		for _, assembly := range src.run.Assemblies {
+			p.printStats(assembly, *top)
+		}
+
+		fmt.Fprintln(p.w, "")
+	}
+
+	return exitCode(cfg, sources, loadFailed)
+}
+
+// Print statistics about the tests in assembly, including the top slowest tests.
+func (p *printer) printStats(assembly xunit.Assembly, top int) {
+	tests := assembly.Tests()
+	counts := make(map[string]int)
+
+	var total float32
+
+	for _, t := range tests {
+		counts[p.speed(t.Time)]++
+		total += t.Time
+	}
+
+	fmt.Fprintln(p.w, "")
+	fmt.Fprintf(p.w, "  Assembly:         %s\r\n", assembly.Name)
+	fmt.Fprintf(p.w, "  # tests:          %v (%v passed, %v failed, %v skipped, %v not run)\r\n",
+		assembly.TotalCount, assembly.PassedCount, assembly.FailedCount, assembly.SkippedCount, assembly.NotRunCount)
+	fmt.Fprintf(p.w, "  Speed:            🚀 %v fast, 🕐 %v normal, 🐌 %v slow\r\n", counts["🚀"], counts["🕐"], counts["🐌"])
+	fmt.Fprintf(p.w, "  Total test time:  %v seconds.\r\n", total)
+
+	if len(tests) > 0 {
+		fmt.Fprintf(p.w, "  Average time:     %v seconds.\r\n", total/float32(len(tests)))
+	}
+
+	sort.SliceStable(tests, func(i, j int) bool { return tests[i].Time > tests[j].Time })
+
+	if top > len(tests) {
+		top = len(tests)
+	}
+
+	if top > 0 {
+		fmt.Fprintln(p.w, "")
+		fmt.Fprintf(p.w, "  Slowest tests:\r\n")
+	}
+
+	for _, t := range tests[:top] {
+		name := strings.Join(append(append([]string{}, t.Groups...), t.Name), " › ")
+
+		fmt.Fprintf(p.w, "    %s %s %s (%v seconds)\r\n", p.speed(t.Time), p.status(t.Result), name, t.Time)
+	}
+}
@@ -0,0 +1,132 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package trx contains functions for parsing XML files containing .NET test result(s) in Visual Studio's TRX format.
+// This is the format that's written by `dotnet test --logger trx`, for any test framework (MSTest, NUnit, xUnit).
+package trx
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// A testRun is the top-level element of the document.
+type testRun struct {
+	XMLName         xml.Name         `xml:"TestRun"`
+	ID              string           `xml:"id,attr"`
+	Name            string           `xml:"name,attr"`
+	RunUser         string           `xml:"runUser,attr"`
+	Times           times            `xml:"Times"`
+	Results         []unitTestResult `xml:"Results>UnitTestResult"`
+	TestDefinitions []unitTest       `xml:"TestDefinitions>UnitTest"`
+	ResultSummary   resultSummary    `xml:"ResultSummary"`
+}
+
+// A times contains the timestamps of the test run.
+type times struct {
+	Creation string `xml:"creation,attr"`
+	Queuing  string `xml:"queuing,attr"`
+	Start    string `xml:"start,attr"`
+	Finish   string `xml:"finish,attr"`
+}
+
+// A unitTestResult contains the result of running a single test.
+type unitTestResult struct {
+	ExecutionID  string           `xml:"executionId,attr"`
+	TestID       string           `xml:"testId,attr"`
+	TestName     string           `xml:"testName,attr"`
+	ComputerName string           `xml:"computerName,attr"`
+	Duration     string           `xml:"duration,attr"`
+	StartTime    string           `xml:"startTime,attr"`
+	EndTime      string           `xml:"endTime,attr"`
+	Outcome      string           `xml:"outcome,attr"`
+	Output       output           `xml:"Output"`
+	InnerResults []unitTestResult `xml:"InnerResults>UnitTestResult"`
+}
+
+// An output contains the output that was captured while running a test.
+type output struct {
+	StdOut    string    `xml:"StdOut"`
+	StdErr    string    `xml:"StdErr"`
+	ErrorInfo errorInfo `xml:"ErrorInfo"`
+}
+
+// An errorInfo contains information about a test failure.
+type errorInfo struct {
+	Message    string `xml:"Message"`
+	StackTrace string `xml:"StackTrace"`
+}
+
+// A unitTest contains the definition of a single test.
+type unitTest struct {
+	ID         string         `xml:"id,attr"`
+	Name       string         `xml:"name,attr"`
+	Storage    string         `xml:"storage,attr"`
+	Categories []testCategory `xml:"TestCategory>TestCategoryItem"`
+	Execution  execution      `xml:"Execution"`
+	TestMethod testMethod     `xml:"TestMethod"`
+}
+
+// A testCategory contains a single category of a test.
+type testCategory struct {
+	Name string `xml:"TestCategory,attr"`
+}
+
+// An execution links the definition of a test to its result.
+type execution struct {
+	ID string `xml:"id,attr"`
+}
+
+// A testMethod contains the information about the method that implements a test.
+type testMethod struct {
+	CodeBase  string `xml:"codeBase,attr"`
+	ClassName string `xml:"className,attr"`
+	Name      string `xml:"name,attr"`
+}
+
+// A resultSummary contains the summary of the test run.
+type resultSummary struct {
+	Outcome  string    `xml:"outcome,attr"`
+	RunInfos []runInfo `xml:"RunInfos>RunInfo"`
+}
+
+// A runInfo contains a message about the test run.
+type runInfo struct {
+	ComputerName string `xml:"computerName,attr"`
+	Outcome      string `xml:"outcome,attr"`
+	Timestamp    string `xml:"timestamp,attr"`
+	Text         string `xml:"Text"`
+}
+
+// Returns a testRun, constructed from the data in rdr.
+func unmarshal(rdr io.Reader) (testRun, error) {
+	var run testRun
+
+	if err := xml.NewDecoder(rdr).Decode(&run); err != nil {
+		return testRun{}, err
+	}
+
+	return run, nil
+}
@@ -0,0 +1,3 @@
+module github.com/kdeconinck/trx
+
+go 1.21.0
@@ -0,0 +1,253 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package trx contains functions for parsing XML files containing .NET test result(s) in Visual Studio's TRX format.
+// This is the format that's written by `dotnet test --logger trx`, for any test framework (MSTest, NUnit, xUnit).
+package trx
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kdeconinck/xunit"
+)
+
+// Load returns a TestRun constructed from the data in rdr.
+// The tests are grouped per assembly, based on the file that contains the test, and the categories of each test are
+// converted into traits named "Category".
+func Load(rdr io.Reader) (xunit.TestRun, error) {
+	data, err := unmarshal(rdr)
+
+	if err != nil {
+		return xunit.TestRun{}, err
+	}
+
+	return readTestRun(data), nil
+}
+
+// An assemblyRun contains the tests of a single assembly, while the test run is being read.
+type assemblyRun struct {
+	name  string           // The name of the assembly.
+	tests []xunit.TestCase // The tests of the assembly.
+	start time.Time        // The time the first test of the assembly started running.
+	end   time.Time        // The time the last test of the assembly finished running.
+	time  float32          // The total number of seconds the tests of the assembly took to run.
+	dur   time.Duration    // The total time the tests of the assembly took to run.
+}
+
+// Read r into a TestRun.
+func readTestRun(r testRun) xunit.TestRun {
+	tRun := xunit.TestRun{
+		User:         r.RunUser,
+		StartTimeRTF: r.Times.Start,
+		EndTimeRTF:   r.Times.Finish,
+		Timestamp:    r.Times.Creation,
+		Assemblies:   make([]xunit.Assembly, 0),
+	}
+
+	definitions := make(map[string]*unitTest, len(r.TestDefinitions))
+
+	for idx := range r.TestDefinitions {
+		definitions[r.TestDefinitions[idx].ID] = &r.TestDefinitions[idx]
+	}
+
+	assemblies := make([]*assemblyRun, 0)
+
+	for _, result := range flatten(r.Results) {
+		if tRun.Computer == "" {
+			tRun.Computer = result.ComputerName
+		}
+
+		def := definitions[result.TestID]
+
+		if def == nil {
+			def = &unitTest{}
+		}
+
+		aRun := findAssembly(&assemblies, def.assemblyName())
+		aRun.add(result, def)
+	}
+
+	for _, aRun := range assemblies {
+		tRun.Assemblies = append(tRun.Assemblies, aRun.toAssembly(r.Times.Start))
+	}
+
+	tRun.ParseTimes()
+
+	return tRun
+}
+
+// Returns results, where each data-driven result is replaced by its inner results.
+func flatten(results []unitTestResult) []unitTestResult {
+	flattened := make([]unitTestResult, 0, len(results))
+
+	for _, result := range results {
+		if len(result.InnerResults) > 0 {
+			flattened = append(flattened, flatten(result.InnerResults)...)
+		} else {
+			flattened = append(flattened, result)
+		}
+	}
+
+	return flattened
+}
+
+// Returns the assembly named name from assemblies, adding it if it doesn't exist yet.
+func findAssembly(assemblies *[]*assemblyRun, name string) *assemblyRun {
+	for _, aRun := range *assemblies {
+		if aRun.name == name {
+			return aRun
+		}
+	}
+
+	aRun := &assemblyRun{name: name}
+	*assemblies = append(*assemblies, aRun)
+
+	return aRun
+}
+
+// Add the test with result, which is defined by def, to aRun.
+func (aRun *assemblyRun) add(result unitTestResult, def *unitTest) {
+	tc := xunit.NewTestCase(result.name(def.TestMethod.ClassName))
+	tc.FullName = result.fullName(def.TestMethod)
+	tc.Result = parseOutcome(result.Outcome)
+	tc.Duration, _ = xunit.ParseTimeSpan(result.Duration)
+	tc.Time = float32(tc.Duration.Seconds())
+	tc.Output = strings.TrimSpace(strings.Join([]string{result.Output.StdOut, result.Output.StdErr}, "\n"))
+
+	switch errInfo := result.Output.ErrorInfo; {
+	case tc.Result == xunit.Fail && (errInfo.Message != "" || errInfo.StackTrace != ""):
+		tc.Failure = &xunit.Failure{Message: strings.TrimSpace(errInfo.Message), StackTrace: errInfo.StackTrace}
+	case tc.Result == xunit.Skip || tc.Result == xunit.NotRun:
+		tc.Reason = strings.TrimSpace(errInfo.Message)
+	}
+
+	for _, category := range def.Categories {
+		tc.Traits = append(tc.Traits, xunit.Trait{Name: "Category", Value: category.Name})
+	}
+
+	if start, err := time.Parse(time.RFC3339Nano, result.StartTime); err == nil {
+		if aRun.start.IsZero() || start.Before(aRun.start) {
+			aRun.start = start
+		}
+	}
+
+	if end, err := time.Parse(time.RFC3339Nano, result.EndTime); err == nil && end.After(aRun.end) {
+		aRun.end = end
+	}
+
+	aRun.time += tc.Time
+	aRun.dur += tc.Duration
+	aRun.tests = append(aRun.tests, tc)
+}
+
+// Returns aRun as an Assembly.
+// The counts are calculated from the tests, and the run date and time are taken from start (the start of the test run).
+func (aRun *assemblyRun) toAssembly(start string) xunit.Assembly {
+	assembly := xunit.Assembly{
+		Name:       aRun.name,
+		TotalCount: len(aRun.tests),
+		Time:       aRun.time,
+		Duration:   aRun.dur,
+		TestGroups: xunit.GroupTests(aRun.tests),
+	}
+
+	if !aRun.start.IsZero() && aRun.end.After(aRun.start) {
+		assembly.Duration = aRun.end.Sub(aRun.start)
+		assembly.Time = float32(assembly.Duration.Seconds())
+	}
+
+	if date, clock, ok := strings.Cut(start, "T"); ok {
+		assembly.RunDate = date
+		assembly.RunTime = clock
+	}
+
+	for _, tc := range aRun.tests {
+		switch tc.Result {
+		case xunit.Pass:
+			assembly.PassedCount++
+		case xunit.Fail:
+			assembly.FailedCount++
+		case xunit.Skip:
+			assembly.SkippedCount++
+		case xunit.NotRun:
+			assembly.NotRunCount++
+		}
+	}
+
+	return assembly
+}
+
+// Returns the name of the test that produced result, including the name of its class (className).
+// Some test frameworks (such as MSTest) only store the name of the method, in which case the name of the class is
+// prepended, so that the test can be grouped in the same way as a test in xUnit's v2+ XML format.
+func (result *unitTestResult) name(className string) string {
+	if className == "" || strings.ContainsAny(result.TestName, " .") {
+		return result.TestName
+	}
+
+	return className + "." + result.TestName
+}
+
+// Returns the fully-qualified name of the test that produced result, which is implemented by method.
+// A test with a display name (or a data row of a test) is qualified by its class and its method, since a display name
+// isn't guaranteed to be unique.
+func (result *unitTestResult) fullName(method testMethod) string {
+	name := result.name(method.ClassName)
+
+	if method.ClassName == "" || method.Name == "" || strings.HasPrefix(name, method.ClassName+"."+method.Name) {
+		return name
+	}
+
+	return method.ClassName + "." + method.Name + ": " + result.TestName
+}
+
+// Returns the name of the assembly which contains the test defined by def.
+func (def *unitTest) assemblyName() string {
+	path := def.TestMethod.CodeBase
+
+	if path == "" {
+		path = def.Storage
+	}
+
+	return path[strings.LastIndexAny(path, "/\\")+1:]
+}
+
+// Returns the Result that corresponds with outcome (the value of the `outcome` attribute of a test result).
+func parseOutcome(outcome string) xunit.Result {
+	switch outcome {
+	case "Passed", "PassedButRunAborted", "Warning":
+		return xunit.Pass
+	case "Failed", "Error", "Timeout", "Aborted":
+		return xunit.Fail
+	case "NotExecuted":
+		return xunit.Skip
+	case "Inconclusive", "NotRunnable", "Disconnected", "Pending", "InProgress":
+		return xunit.NotRun
+	default:
+		return xunit.Unknown
+	}
+}
@@ -0,0 +1,274 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Quality assurance: Verify (and measure the performance) of the public API of the "trx" package.
+package trx_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kdeconinck/assert"
+	"github.com/kdeconinck/trx"
+	"github.com/kdeconinck/xunit"
+)
+
+// UT: Load an XML file containing a .NET test result in TRX format.
+func TestLoad(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		xmlData string
+		want    xunit.TestRun
+		wantErr bool
+	}{
+		{
+			xmlData: "<assemblies />",
+			wantErr: true,
+		},
+		{
+			xmlData: "<TestRun id=\"1\" runUser=\"DOMAIN\\Kevin\" xmlns=\"http://microsoft.com/schemas/VisualStudio/TeamTest/2010\">\n" +
+				"  <Times creation=\"2023-10-07T20:53:18.0000000+02:00\" start=\"2023-10-07T20:53:19.0000000+02:00\" finish=\"2023-10-07T20:53:21.0000000+02:00\" />\n" +
+				"  <Results>\n" +
+				"    <UnitTestResult testId=\"t1\" testName=\"NS.CalculatorTests+Add.ReturnsSum\" computerName=\"WIN11\" duration=\"00:00:00.2500000\" startTime=\"2023-10-07T20:53:19.0000000+02:00\" endTime=\"2023-10-07T20:53:19.2500000+02:00\" outcome=\"Passed\">\n" +
+				"      <Output><StdOut>Adding numbers.</StdOut></Output>\n" +
+				"    </UnitTestResult>\n" +
+				"    <UnitTestResult testId=\"t2\" testName=\"DividesByZero\" computerName=\"WIN11\" duration=\"00:00:01.5000000\" startTime=\"2023-10-07T20:53:19.2500000+02:00\" endTime=\"2023-10-07T20:53:20.7500000+02:00\" outcome=\"Failed\">\n" +
+				"      <Output>\n" +
+				"        <ErrorInfo>\n" +
+				"          <Message>Attempted to divide by zero.</Message>\n" +
+				"          <StackTrace>   at NS.CalculatorTests.DividesByZero()</StackTrace>\n" +
+				"        </ErrorInfo>\n" +
+				"      </Output>\n" +
+				"    </UnitTestResult>\n" +
+				"    <UnitTestResult testId=\"t3\" testName=\"Is skipped\" computerName=\"WIN11\" duration=\"00:00:00\" outcome=\"NotExecuted\">\n" +
+				"      <Output><ErrorInfo><Message>Not implemented yet.</Message></ErrorInfo></Output>\n" +
+				"    </UnitTestResult>\n" +
+				"    <UnitTestResult testId=\"t4\" testName=\"Other.Tests.Test\" computerName=\"WIN11\" duration=\"00:00:00.1000000\" outcome=\"Passed\">\n" +
+				"      <InnerResults>\n" +
+				"        <UnitTestResult testId=\"t4\" testName=\"Test (1)\" duration=\"00:00:00.0500000\" outcome=\"Passed\" />\n" +
+				"        <UnitTestResult testId=\"t4\" testName=\"Test (2)\" duration=\"00:00:00.0500000\" outcome=\"Inconclusive\" />\n" +
+				"      </InnerResults>\n" +
+				"    </UnitTestResult>\n" +
+				"  </Results>\n" +
+				"  <TestDefinitions>\n" +
+				"    <UnitTest id=\"t1\" name=\"ReturnsSum\" storage=\"c:\\src\\app.tests.dll\">\n" +
+				"      <TestMethod codeBase=\"C:\\src\\App.Tests.dll\" className=\"NS.CalculatorTests+Add\" name=\"ReturnsSum\" />\n" +
+				"    </UnitTest>\n" +
+				"    <UnitTest id=\"t2\" name=\"DividesByZero\" storage=\"c:\\src\\app.tests.dll\">\n" +
+				"      <TestCategory><TestCategoryItem TestCategory=\"Unit\" /></TestCategory>\n" +
+				"      <TestMethod codeBase=\"C:\\src\\App.Tests.dll\" className=\"NS.CalculatorTests\" name=\"DividesByZero\" />\n" +
+				"    </UnitTest>\n" +
+				"    <UnitTest id=\"t3\" name=\"Is skipped\" storage=\"c:\\src\\app.tests.dll\">\n" +
+				"      <TestMethod codeBase=\"C:\\src\\App.Tests.dll\" className=\"NS.CalculatorTests\" name=\"IsSkipped\" />\n" +
+				"    </UnitTest>\n" +
+				"    <UnitTest id=\"t4\" name=\"Test\" storage=\"/src/other.tests.dll\">\n" +
+				"      <TestMethod className=\"Other.Tests\" name=\"Test\" />\n" +
+				"    </UnitTest>\n" +
+				"  </TestDefinitions>\n" +
+				"</TestRun>",
+			want: xunit.TestRun{
+				Computer:      "WIN11",
+				User:          "DOMAIN\\Kevin",
+				StartTimeRTF:  "2023-10-07T20:53:19.0000000+02:00",
+				EndTimeRTF:    "2023-10-07T20:53:21.0000000+02:00",
+				Timestamp:     "2023-10-07T20:53:18.0000000+02:00",
+				StartTime:     time.Date(2023, 10, 7, 18, 53, 19, 0, time.UTC),
+				EndTime:       time.Date(2023, 10, 7, 18, 53, 21, 0, time.UTC),
+				TimestampTime: time.Date(2023, 10, 7, 18, 53, 18, 0, time.UTC),
+				Assemblies: []xunit.Assembly{
+					{
+						Name:         "App.Tests.dll",
+						PassedCount:  1,
+						FailedCount:  1,
+						SkippedCount: 1,
+						TotalCount:   3,
+						RunDate:      "2023-10-07",
+						RunTime:      "20:53:19.0000000+02:00",
+						Time:         1.75,
+						RunStart:     time.Date(2023, 10, 7, 18, 53, 19, 0, time.UTC),
+						Duration:     1750 * time.Millisecond,
+						TestGroups: []*xunit.TestGroup{
+							{
+								Name: "",
+								Tests: []xunit.TestCase{
+									{Name: "Is skipped", FullName: "NS.CalculatorTests.IsSkipped: Is skipped", Result: xunit.Skip, Reason: "Not implemented yet."},
+								},
+								Groups: []*xunit.TestGroup{
+									{
+										Name: "Calculator tests",
+										Groups: []*xunit.TestGroup{
+											{
+												Name: "Add",
+												Tests: []xunit.TestCase{
+													{
+														Name:     "Returns sum",
+														FullName: "NS.CalculatorTests+Add.ReturnsSum",
+														Result:   xunit.Pass,
+														Time:     0.25,
+														Duration: 250 * time.Millisecond,
+														Output:   "Adding numbers.",
+														Groups:   []string{"Calculator tests", "Add"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							{
+								Name: "Category - Unit",
+								Tests: []xunit.TestCase{
+									{
+										Name:     "Divides by zero",
+										FullName: "NS.CalculatorTests.DividesByZero",
+										Result:   xunit.Fail,
+										Time:     1.5,
+										Duration: 1500 * time.Millisecond,
+										Failure: &xunit.Failure{
+											Message:    "Attempted to divide by zero.",
+											StackTrace: "   at NS.CalculatorTests.DividesByZero()",
+										},
+										Traits: []xunit.Trait{{Name: "Category", Value: "Unit"}},
+									},
+								},
+							},
+						},
+					},
+					{
+						Name:        "other.tests.dll",
+						PassedCount: 1,
+						NotRunCount: 1,
+						TotalCount:  2,
+						RunDate:     "2023-10-07",
+						RunTime:     "20:53:19.0000000+02:00",
+						Time:        0.1,
+						RunStart:    time.Date(2023, 10, 7, 18, 53, 19, 0, time.UTC),
+						Duration:    100 * time.Millisecond,
+						TestGroups: []*xunit.TestGroup{
+							{
+								Name: "",
+								Tests: []xunit.TestCase{
+									{Name: "Test (1)", FullName: "Other.Tests.Test: Test (1)", Result: xunit.Pass, Time: 0.05, Duration: 50 * time.Millisecond},
+									{Name: "Test (2)", FullName: "Other.Tests.Test: Test (2)", Result: xunit.NotRun, Time: 0.05, Duration: 50 * time.Millisecond},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	} {
+		// HELPER FUNCTIONS.
+		fmtValue := func(v xunit.TestRun) string {
+			b, _ := json.MarshalIndent(v, "", "  ")
+			res := strings.Replace(string(b), "\n", "\n            ", -1)
+
+			return res
+		}
+
+		fmtXml := func(v string) string {
+			v = strings.Replace(v, "\n", "\n            ", -1)
+
+			return v
+		}
+
+		// ARRANGE.
+		rdr := strings.NewReader(tc.xmlData)
+
+		// ACT.
+		got, err := trx.Load(rdr)
+
+		// ASSERT.
+		if tc.wantErr {
+			assert.NotNil(t, err, "", "\n\n"+
+				"UT Name:    Parse an invalid XML file containing a .NET test result in TRX format.\n"+
+				"XML Input:  %s\n"+
+				"\033[32mExpected:   Error, NOT <nil>\033[0m\n"+
+				"\033[31mActual:     Error, %v\033[0m\n\n", fmtXml(tc.xmlData), err)
+		}
+
+		if !tc.wantErr {
+			assert.Nil(t, err, "", "\n\n"+
+				"UT Name:    Parse a valid XML file containing a .NET test result in TRX format.\n"+
+				"XML Input:  %s\n"+
+				"\033[32mExpected:   Error, <nil>\033[0m\n"+
+				"\033[31mActual:     Error, %v\033[0m\n\n", fmtXml(tc.xmlData), err)
+		}
+
+		assert.EqualFn(t, got, tc.want, func(got xunit.TestRun, want xunit.TestRun) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Parse an XML file containing a .NET test result in TRX format.\n"+
+			"XML Input:  %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n",
+			fmtXml(tc.xmlData), fmtValue(tc.want), fmtValue(got))
+	}
+}
+
+// UT: Load a .NET test result in TRX format, which contains tests with the same name in different classes.
+func TestLoad_SameNames(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	rdr := strings.NewReader("<TestRun>\n" +
+		"  <Results>\n" +
+		"    <UnitTestResult testId=\"t1\" testName=\"Adds numbers\" outcome=\"Passed\" />\n" +
+		"    <UnitTestResult testId=\"t2\" testName=\"Adds numbers\" outcome=\"Failed\" />\n" +
+		"  </Results>\n" +
+		"  <TestDefinitions>\n" +
+		"    <UnitTest id=\"t1\" storage=\"/src/app.tests.dll\"><TestMethod className=\"NS.First\" name=\"Add\" /></UnitTest>\n" +
+		"    <UnitTest id=\"t2\" storage=\"/src/app.tests.dll\"><TestMethod className=\"NS.Second\" name=\"Add\" /></UnitTest>\n" +
+		"  </TestDefinitions>\n" +
+		"</TestRun>")
+
+	// ACT.
+	tRun, err := trx.Load(rdr)
+
+	// ASSERT.
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Load a .NET test result in TRX format, which contains tests with the same name in different classes.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+
+	var failed int
+
+	tests := tRun.Assemblies[0].Tests()
+
+	for _, tc := range tests {
+		if tc.Result == xunit.Fail {
+			failed++
+		}
+	}
+
+	assert.Equal(t, [2]int{len(tests), failed}, [2]int{2, 1}, "", "\n\n"+
+		"UT Name:    Load a .NET test result in TRX format, which contains tests with the same name in different classes.\n"+
+		"\033[32mExpected:   Tests: 2, Failed: 1\033[0m\n"+
+		"\033[31mActual:     Tests: %v, Failed: %v\033[0m\n\n", len(tests), failed)
+}
@@ -23,13 +23,17 @@
 // =                OTHER DEALINGS IN THE SOFTWARE.
 // =====================================================================================================================
 
-// Package xunit contains functions for parsing XML files containing .NET test result(s) in xUnit's v2+ XML format.
+// Package xunit contains functions for parsing files containing .NET test result(s) in xUnit's v2+ XML format, or in
+// the JSON message stream that's written by xUnit v3, and for merging test result(s) and writing them in xUnit's v2
+// XML format.
 // More information regarding this format can be found @ https://xunit.net/docs/format-xml-v2.
 package xunit
 
 import (
 	"encoding/xml"
 	"io"
+
+	"github.com/kdeconinck/slices"
 )
 
 // A result is the top-level element of the document. It's the result of a `dotnet test` operation in xUnit's v2+ XML
@@ -64,14 +68,11 @@ type assembly struct {
 	StartRTF        string       `xml:"start-rtf,attr"`
 	TargetFramework string       `xml:"target-framework,attr"`
 	TestFramework   string       `xml:"test-framework,attr"`
-	Time            float32      `xml:"time,attr"`
+	Time            float64      `xml:"time,attr"`
 	TimeRTF         string       `xml:"time-rtf,attr"`
 	Total           int          `xml:"total,attr"`
 	Collections     []collection `xml:"collection"`
 	ErrorSet        errorSet     `xml:"errors"`
-
-	// Calculated fields.
-	testMap map[string][]TestCase // A map that contains all the tests of the assembly, grouped by trait.
 }
 
 // A collection contains information about the run of a single test collection.
@@ -96,7 +97,7 @@ type test struct {
 	Result     string     `xml:"result,attr"`
 	SourceFile string     `xml:"source-file,attr"`
 	SourceLine string     `xml:"source-line,attr"`
-	Time       float32    `xml:"time,attr"`
+	Time       float64    `xml:"time,attr"`
 	TimeRTF    string     `xml:"time-rtf,attr"`
 	Type       string     `xml:"type,attr"`
 	Failure    failure    `xml:"failure"`
@@ -137,19 +138,239 @@ type errorSet struct {
 // An err contains information about an environment failure that happened outside the scope of running a single unit
 // test (for example, an exception thrown while disposing of a fixture object).
 type err struct {
-	Name string `xml:"name,attr"`
-	Type string `xml:"type,attr"`
+	Name    string  `xml:"name,attr"`
+	Type    string  `xml:"type,attr"`
+	Failure failure `xml:"failure"`
+}
+
+// A handler contains the functions that are called by decode for each element that's decoded.
+// A <nil> function is skipped.
+type handler struct {
+	result     func(r *result) error     // Called for the root element, without its assemblies.
+	assembly   func(a *assembly) error   // Called when an assembly starts, without its collections.
+	collection func(c *collection) error // Called when a collection starts, without its tests.
+	test       func(t *test) error       // Called for each test.
+	errorSet   func(e *errorSet) error   // Called for the environmental errors of the current assembly.
+}
+
+// A tokenList is an xml.TokenReader that returns a fixed list of tokens.
+type tokenList []xml.Token
+
+// Token returns the next token of l, or io.EOF if there are no more tokens.
+func (l *tokenList) Token() (xml.Token, error) {
+	if len(*l) == 0 {
+		return nil, io.EOF
+	}
+
+	tok := (*l)[0]
+	*l = (*l)[1:]
+
+	return tok, nil
 }
 
 // Returns a result, constructed from the data in rdr.
 func unmarshal(rdr io.Reader) (result, error) {
 	var res result
 
-	if bytes, err := io.ReadAll(rdr); err == nil {
-		if err := xml.Unmarshal(bytes, &res); err != nil {
-			return result{}, err
-		}
+	err := decode(rdr, handler{
+		result: func(r *result) error {
+			res = *r
+
+			return nil
+		},
+		assembly: func(a *assembly) error {
+			res.Assemblies = append(res.Assemblies, *a)
+
+			return nil
+		},
+		collection: func(c *collection) error {
+			cAssembly := &res.Assemblies[len(res.Assemblies)-1]
+			cAssembly.Collections = append(cAssembly.Collections, *c)
+
+			return nil
+		},
+		test: func(t *test) error {
+			cAssembly := &res.Assemblies[len(res.Assemblies)-1]
+			cCollection := &cAssembly.Collections[len(cAssembly.Collections)-1]
+			cCollection.Tests = append(cCollection.Tests, *t)
+
+			return nil
+		},
+		errorSet: func(e *errorSet) error {
+			cAssembly := &res.Assemblies[len(res.Assemblies)-1]
+			cAssembly.ErrorSet.Errors = append(cAssembly.ErrorSet.Errors, e.Errors...)
+
+			return nil
+		},
+	})
+
+	if err != nil {
+		return result{}, err
 	}
 
 	return res, nil
 }
+
+// The values of the `schema-version` attribute that are supported.
+// An empty value is supported as well, since older versions of xUnit don't write this attribute. Version 3 is written
+// by xUnit v3, which adds some attributes (such as `id`, `start-rtf` and `finish-rtf`) to the existing elements.
+var supportedSchemaVersions = []string{"", "1", "2", "3"}
+
+// A decoder reads the elements of xUnit's v2+ XML format one at a time.
+type decoder struct {
+	dec *xml.Decoder // The XML decoder.
+	rdr *errReader   // The reader the XML decoder reads from.
+	h   handler      // The functions that are called for each element that's decoded.
+}
+
+// An errReader is an io.Reader which remembers the first error (other than io.EOF) returned by the reader it wraps.
+type errReader struct {
+	rdr io.Reader // The reader to read from.
+	err error     // The first error returned by rdr, other than io.EOF.
+}
+
+// Read reads from the wrapped reader into p.
+func (r *errReader) Read(p []byte) (int, error) {
+	n, err := r.rdr.Read(p)
+
+	if err != nil && err != io.EOF && r.err == nil {
+		r.err = err
+	}
+
+	return n, err
+}
+
+// Decode the data in rdr, calling the functions of h as soon as an element is decoded.
+// The document is read token by token, so only a single test is kept in memory at any time.
+func decode(rdr io.Reader, h handler) error {
+	eRdr := &errReader{rdr: rdr}
+	d := &decoder{dec: xml.NewDecoder(eRdr), rdr: eRdr, h: h}
+	depth := 0
+	hasRoot := false
+
+	for {
+		tok, err := d.dec.Token()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return d.wrap(err)
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			descended, err := d.decodeElement(el, depth)
+
+			if err != nil {
+				return err
+			}
+
+			if descended {
+				hasRoot = true
+				depth++
+			}
+
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	if !hasRoot {
+		return &RootElementError{}
+	}
+
+	return nil
+}
+
+// Decode el, which is found at depth.
+// Elements that contain other elements that should be decoded are read without their content, in which case true is
+// returned. All the other elements are read completely (or skipped if they're unknown).
+func (d *decoder) decodeElement(el xml.StartElement, depth int) (bool, error) {
+	switch {
+	case depth == 0:
+		var r result
+
+		if el.Name.Local != "assemblies" {
+			return false, &RootElementError{Name: el.Name.Local}
+		}
+
+		if err := decodeAttrs(el, &r); err != nil {
+			return false, d.wrap(err)
+		}
+
+		if !slices.Contains(supportedSchemaVersions, r.SchemaVersion) {
+			return false, &SchemaVersionError{Version: r.SchemaVersion}
+		}
+
+		return true, call(d.h.result, &r)
+
+	case depth == 1 && el.Name.Local == "assembly":
+		var a assembly
+
+		return true, callWith(d, decodeAttrs(el, &a), d.h.assembly, &a)
+
+	case depth == 2 && el.Name.Local == "collection":
+		var c collection
+
+		return true, callWith(d, decodeAttrs(el, &c), d.h.collection, &c)
+
+	case depth == 2 && el.Name.Local == "errors":
+		var e errorSet
+
+		return false, callWith(d, d.dec.DecodeElement(&e, &el), d.h.errorSet, &e)
+
+	case depth == 3 && el.Name.Local == "test":
+		var t test
+
+		return false, callWith(d, d.dec.DecodeElement(&t, &el), d.h.test, &t)
+
+	default:
+		if err := d.dec.Skip(); err != nil {
+			return false, d.wrap(err)
+		}
+
+		return false, nil
+	}
+}
+
+// Returns err, which is returned while decoding, as a ReadError or as a SyntaxError.
+func (d *decoder) wrap(err error) error {
+	if d.rdr.err != nil {
+		return &ReadError{Err: d.rdr.err}
+	}
+
+	line, column := d.dec.InputPos()
+	msg := err.Error()
+
+	if sErr, ok := err.(*xml.SyntaxError); ok {
+		line = sErr.Line
+		msg = sErr.Msg
+	}
+
+	return &SyntaxError{Format: "XML", Line: line, Column: column, Msg: msg, Err: err}
+}
+
+// Decode the attributes of el into v, ignoring the content of el.
+func decodeAttrs(el xml.StartElement, v any) error {
+	return xml.NewTokenDecoder(&tokenList{el, el.End()}).Decode(v)
+}
+
+// Returns err (as a ReadError or as a SyntaxError) if it isn't <nil>, otherwise, fn is called with v.
+func callWith[V any](d *decoder, err error, fn func(v *V) error, v *V) error {
+	if err != nil {
+		return d.wrap(err)
+	}
+
+	return call(fn, v)
+}
+
+// Returns the result of calling fn with v, or <nil> if fn is <nil>.
+func call[V any](fn func(v *V) error, v *V) error {
+	if fn == nil {
+		return nil
+	}
+
+	return fn(v)
+}
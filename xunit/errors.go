@@ -0,0 +1,91 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package xunit contains functions for parsing files containing .NET test result(s) in xUnit's v2+ XML format, or in
+// the JSON message stream that's written by xUnit v3, and for merging test result(s) and writing them in xUnit's v2
+// XML format.
+// More information regarding this format can be found @ https://xunit.net/docs/format-xml-v2.
+package xunit
+
+import "fmt"
+
+// ReadError is returned when the data can't be read.
+type ReadError struct {
+	Err error // The error returned by the reader.
+}
+
+// Error returns the description of e.
+func (e *ReadError) Error() string {
+	return fmt.Sprintf("failed to read the data: %v", e.Err)
+}
+
+// Unwrap returns the error returned by the reader.
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// SyntaxError is returned when the data isn't well-formed XML (or JSON), or when it contains invalid values.
+// A truncated file results in a SyntaxError as well.
+type SyntaxError struct {
+	Format string // The format of the data, either "XML" or "JSON".
+	Line   int    // The line (1-based) at which the error was detected.
+	Column int    // The column (1-based) at which the error was detected.
+	Msg    string // The description of the error.
+	Err    error  // The underlying error.
+}
+
+// Error returns the description of e.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("malformed %s at line %v, column %v: %s", e.Format, e.Line, e.Column, e.Msg)
+}
+
+// Unwrap returns the underlying error.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// RootElementError is returned when the root element of the data isn't <assemblies>.
+type RootElementError struct {
+	Name string // The name of the root element, empty if the data doesn't contain any element.
+}
+
+// Error returns the description of e.
+func (e *RootElementError) Error() string {
+	if e.Name == "" {
+		return "no root element found, expected <assemblies>"
+	}
+
+	return fmt.Sprintf("unexpected root element <%s>, expected <assemblies>", e.Name)
+}
+
+// SchemaVersionError is returned when the data is written in a version of xUnit's XML format that isn't supported.
+type SchemaVersionError struct {
+	Version string // The value of the `schema-version` attribute of the root element.
+}
+
+// Error returns the description of e.
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("unsupported schema version '%s'", e.Version)
+}
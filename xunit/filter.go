@@ -0,0 +1,214 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package xunit contains functions for parsing files containing .NET test result(s) in xUnit's v2+ XML format, or in
+// the JSON message stream that's written by xUnit v3, and for merging test result(s) and writing them in xUnit's v2
+// XML format.
+// More information regarding this format can be found @ https://xunit.net/docs/format-xml-v2.
+package xunit
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/kdeconinck/slices"
+)
+
+// A Filter selects the tests of a TestRun.
+// A test is selected when it matches each criterion that's set; a criterion that isn't set matches every test.
+type Filter struct {
+	Results     []Result       // The results of the selected tests.
+	Traits      []Trait        // The traits of the selected tests, a test is selected when it has any of them.
+	Assemblies  []string       // The names of the assemblies of the selected tests, with or without their path.
+	Name        *regexp.Regexp // Matches the name, or the fully-qualified name, of the selected tests.
+	ExcludeName *regexp.Regexp // Matches the name, or the fully-qualified name, of the tests that aren't selected.
+}
+
+// Apply returns a copy of tRun which only contains the tests that are selected by f.
+// Groups without any selected tests are removed, and the counts of each assembly are recomputed from its selected
+// tests, as are the counts and the durations of its collections. Collections without any selected tests are removed.
+// When f selects specific traits, the groups of the other traits are removed as well, and when f selects
+// tests (rather than assemblies only), assemblies without any selected tests are removed.
+func (f Filter) Apply(tRun TestRun) TestRun {
+	filtered := tRun
+	filtered.Assemblies = make([]Assembly, 0, len(tRun.Assemblies))
+
+	for _, assembly := range tRun.Assemblies {
+		if !f.MatchAssembly(assembly.Name) {
+			continue
+		}
+
+		if !f.selectsTests() {
+			filtered.Assemblies = append(filtered.Assemblies, assembly)
+
+			continue
+		}
+
+		assembly.TestGroups = f.applyGroups(assembly.TestGroups, true)
+
+		if len(assembly.TestGroups) == 0 {
+			continue
+		}
+
+		assembly.recount()
+		assembly.recountCollections()
+		filtered.Assemblies = append(filtered.Assemblies, assembly)
+	}
+
+	return filtered
+}
+
+// MatchAssembly returns true if the assembly named name is selected by f, false otherwise.
+// The name is compared case-insensitively, with and without its path.
+func (f Filter) MatchAssembly(name string) bool {
+	if len(f.Assemblies) == 0 {
+		return true
+	}
+
+	base := name[strings.LastIndexAny(name, `/\`)+1:]
+
+	for _, v := range f.Assemblies {
+		if strings.EqualFold(v, name) || strings.EqualFold(v, base) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match returns true if tc is selected by f, false otherwise.
+// The assemblies that are selected by f aren't taken into account, use MatchAssembly for that.
+func (f Filter) Match(tc TestCase) bool {
+	if len(f.Results) > 0 && !slices.Contains(f.Results, tc.Result) {
+		return false
+	}
+
+	if len(f.Traits) > 0 && !f.matchTraits(tc.Traits) {
+		return false
+	}
+
+	if f.Name != nil && !f.Name.MatchString(tc.Name) && !f.Name.MatchString(tc.FullName) {
+		return false
+	}
+
+	if f.ExcludeName != nil && (f.ExcludeName.MatchString(tc.Name) || f.ExcludeName.MatchString(tc.FullName)) {
+		return false
+	}
+
+	return true
+}
+
+// Recompute the number of tests of each collection of a, per result, and its duration, from the tests of a.
+// Collections without any tests are removed.
+func (a *Assembly) recountCollections() {
+	tests := a.Tests()
+	collections := make([]Collection, 0, len(a.Collections))
+
+	for _, collection := range a.Collections {
+		collection.PassedCount, collection.FailedCount, collection.SkippedCount = 0, 0, 0
+		collection.NotRunCount, collection.TotalCount = 0, 0
+		collection.Time, collection.TimeRTF, collection.Duration = 0, "", 0
+
+		for _, tc := range tests {
+			if tc.Collection != collection.Name {
+				continue
+			}
+
+			collection.TotalCount++
+			collection.Time += tc.Time
+			collection.Duration += tc.Duration
+
+			switch tc.Result {
+			case Pass:
+				collection.PassedCount++
+			case Fail:
+				collection.FailedCount++
+			case Skip:
+				collection.SkippedCount++
+			case NotRun:
+				collection.NotRunCount++
+			}
+		}
+
+		if collection.TotalCount > 0 {
+			collections = append(collections, collection)
+		}
+	}
+
+	a.Collections = collections
+}
+
+// Returns true if f selects tests based on their properties, false if it only selects assemblies.
+func (f Filter) selectsTests() bool {
+	return len(f.Results) > 0 || len(f.Traits) > 0 || f.Name != nil || f.ExcludeName != nil
+}
+
+// Returns true if traits contains any of the traits that are selected by f, false otherwise.
+func (f Filter) matchTraits(traits []Trait) bool {
+	for _, t := range traits {
+		if f.matchTrait(t.friendlyName()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns true if the trait with the friendly name name is selected by f, false otherwise.
+func (f Filter) matchTrait(name string) bool {
+	for _, t := range f.Traits {
+		if t.friendlyName() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns a copy of groups which only contains the tests that are selected by f, without any empty groups.
+// When root is true, groups are the groups of an assembly, which are named after a trait.
+func (f Filter) applyGroups(groups []*TestGroup, root bool) []*TestGroup {
+	filtered := make([]*TestGroup, 0, len(groups))
+
+	for _, group := range groups {
+		if root && len(f.Traits) > 0 && !f.matchTrait(group.Name) {
+			continue
+		}
+
+		fGroup := &TestGroup{Name: group.Name, Tests: make([]TestCase, 0, len(group.Tests))}
+
+		for _, tc := range group.Tests {
+			if f.Match(tc) {
+				fGroup.Tests = append(fGroup.Tests, tc)
+			}
+		}
+
+		if fGroup.Groups = f.applyGroups(group.Groups, false); len(fGroup.Tests) > 0 || len(fGroup.Groups) > 0 {
+			filtered = append(filtered, fGroup)
+		}
+	}
+
+	return filtered
+}
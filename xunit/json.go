@@ -0,0 +1,348 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package xunit contains functions for parsing files containing .NET test result(s) in xUnit's v2+ XML format, or in
+// the JSON message stream that's written by xUnit v3, and for merging test result(s) and writing them in xUnit's v2
+// XML format.
+// More information regarding this format can be found @ https://xunit.net/docs/format-xml-v2.
+package xunit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/kdeconinck/maps"
+)
+
+// A message is a single message of xUnit's v3 JSON message stream.
+// Only the fields that are needed to construct a TestRun are decoded, and all the messages share the same structure.
+type message struct {
+	Type             string              `json:"$type"`
+	AssemblyUniqueID string              `json:"AssemblyUniqueID"`
+	AssemblyName     string              `json:"AssemblyName"`
+	AssemblyPath     string              `json:"AssemblyPath"`
+	ConfigFilePath   string              `json:"ConfigFilePath"`
+	TargetFramework  string              `json:"TargetFramework"`
+	TestEnvironment  string              `json:"TestEnvironment"`
+	TestFramework    string              `json:"TestFrameworkDisplayName"`
+	StartTime        string              `json:"StartTime"`
+	FinishTime       string              `json:"FinishTime"`
+	CollectionID     string              `json:"TestCollectionUniqueID"`
+	CollectionName   string              `json:"TestCollectionDisplayName"`
+	ExecutionTime    float64             `json:"ExecutionTime"`
+	TestCaseID       string              `json:"TestCaseUniqueID"`
+	TestClassName    string              `json:"TestClassName"`
+	TestMethodName   string              `json:"TestMethodName"`
+	TestUniqueID     string              `json:"TestUniqueID"`
+	TestDisplayName  string              `json:"TestDisplayName"`
+	Traits           map[string][]string `json:"Traits"`
+	Output           string              `json:"Output"`
+	Warnings         []string            `json:"Warnings"`
+	Reason           string              `json:"Reason"`
+	ExceptionTypes   []string            `json:"ExceptionTypes"`
+	Messages         []string            `json:"Messages"`
+	StackTraces      []string            `json:"StackTraces"`
+}
+
+// A messageRun contains the state of a test run, while the messages of xUnit's v3 JSON message stream are read.
+type messageRun struct {
+	tRun       TestRun                     // The test run, without any assemblies.
+	assemblies []*messageAssembly          // The assemblies, in the order they're started.
+	testCases  map[string]message          // The `test-case-starting` message of each test case that's running, by ID.
+	starting   map[string]message          // The `test-starting` message of each test that's running, by ID.
+	byID       map[string]*messageAssembly // The assemblies, by ID.
+}
+
+// A messageAssembly contains an assembly, together with its tests, while the messages are read.
+type messageAssembly struct {
+	assembly    Assembly       // The assembly, without any tests.
+	tests       []TestCase     // The tests of the assembly.
+	collections map[string]int // The index of each collection (in the collections of the assembly), by ID.
+}
+
+// LoadJSON returns a TestRun constructed from the data in rdr, which contains the messages that are written by the
+// JSON reporter of xUnit v3 (one message per line).
+// Messages that aren't relevant for a TestRun are ignored.
+func LoadJSON(rdr io.Reader) (TestRun, error) {
+	mRun := &messageRun{
+		testCases: make(map[string]message),
+		starting:  make(map[string]message),
+		byID:      make(map[string]*messageAssembly),
+	}
+	bRdr := bufio.NewReader(rdr)
+
+	for line := 1; ; line++ {
+		data, err := bRdr.ReadBytes('\n')
+
+		if err != nil && err != io.EOF {
+			return TestRun{}, &ReadError{Err: err}
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			var msg message
+
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return TestRun{}, jsonError(err, line)
+			}
+
+			mRun.handle(msg)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return mRun.toTestRun(), nil
+}
+
+// Returns err, which is returned while decoding the message on line, as a SyntaxError.
+func jsonError(err error, line int) error {
+	sErr := &SyntaxError{Format: "JSON", Line: line, Column: 1, Msg: err.Error(), Err: err}
+
+	var synErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &synErr):
+		sErr.Column = int(synErr.Offset)
+	case errors.As(err, &typeErr):
+		sErr.Column = int(typeErr.Offset)
+	}
+
+	return sErr
+}
+
+// Update mRun with the information in msg.
+func (mRun *messageRun) handle(msg message) {
+	switch msg.Type {
+	case "test-assembly-starting":
+		mAssembly := mRun.assembly(msg.AssemblyUniqueID)
+		mAssembly.assembly.ID = msg.AssemblyUniqueID
+		mAssembly.assembly.Name = msg.assemblyName()
+		mAssembly.assembly.ConfigFile = msg.ConfigFilePath
+		mAssembly.assembly.Environment = msg.TestEnvironment
+		mAssembly.assembly.TargetFramework = msg.TargetFramework
+		mAssembly.assembly.TestFramework = msg.TestFramework
+		mAssembly.assembly.StartTimeRTF = msg.StartTime
+
+		if date, clock, ok := strings.Cut(msg.StartTime, "T"); ok {
+			mAssembly.assembly.RunDate = date
+			mAssembly.assembly.RunTime = clock
+		}
+
+		if mRun.tRun.StartTimeRTF == "" {
+			mRun.tRun.StartTimeRTF = msg.StartTime
+		}
+
+	case "test-assembly-finished":
+		mAssembly := mRun.assembly(msg.AssemblyUniqueID)
+		mAssembly.assembly.Time = float32(msg.ExecutionTime)
+		mAssembly.assembly.Duration = secondsToDuration(msg.ExecutionTime)
+		mAssembly.assembly.EndTimeRTF = msg.FinishTime
+		mRun.tRun.EndTimeRTF = msg.FinishTime
+
+	case "test-collection-starting":
+		collection := mRun.assembly(msg.AssemblyUniqueID).collection(msg.CollectionID)
+		collection.ID = msg.CollectionID
+		collection.Name = msg.CollectionName
+
+	case "test-collection-finished":
+		collection := mRun.assembly(msg.AssemblyUniqueID).collection(msg.CollectionID)
+		collection.Time = float32(msg.ExecutionTime)
+		collection.Duration = secondsToDuration(msg.ExecutionTime)
+
+	case "test-case-starting":
+		mRun.testCases[msg.TestCaseID] = msg
+
+	case "test-case-finished":
+		delete(mRun.testCases, msg.TestCaseID)
+
+	case "test-starting":
+		mRun.starting[msg.TestUniqueID] = msg
+
+	case "test-passed", "test-failed", "test-skipped", "test-not-run":
+		starting := mRun.starting[msg.TestUniqueID]
+		delete(mRun.starting, msg.TestUniqueID)
+
+		mAssembly := mRun.assembly(msg.AssemblyUniqueID)
+		tCase := msg.toTestCase(starting, mRun.testCases[starting.TestCaseID])
+
+		if msg.CollectionID != "" {
+			collection := mAssembly.collection(msg.CollectionID)
+			collection.count(tCase.Result)
+			tCase.Collection = collection.Name
+		}
+
+		mAssembly.tests = append(mAssembly.tests, tCase)
+
+	default:
+		if strings.HasSuffix(msg.Type, "-cleanup-failure") && msg.AssemblyUniqueID != "" {
+			mAssembly := mRun.assembly(msg.AssemblyUniqueID)
+			mAssembly.assembly.ErrorCount++
+			mAssembly.assembly.Errors = append(mAssembly.assembly.Errors, EnvironmentError{
+				Type:    strings.TrimSuffix(msg.Type, "-failure"),
+				Failure: msg.toFailure(),
+			})
+		}
+	}
+}
+
+// Returns the assembly with the given id, adding it if it doesn't exist yet.
+func (mRun *messageRun) assembly(id string) *messageAssembly {
+	if mAssembly, ok := mRun.byID[id]; ok {
+		return mAssembly
+	}
+
+	mAssembly := &messageAssembly{collections: make(map[string]int)}
+	mRun.byID[id] = mAssembly
+	mRun.assemblies = append(mRun.assemblies, mAssembly)
+
+	return mAssembly
+}
+
+// Returns the collection of mAssembly with the given id, adding it if it doesn't exist yet.
+func (mAssembly *messageAssembly) collection(id string) *Collection {
+	idx, ok := mAssembly.collections[id]
+
+	if !ok {
+		idx = len(mAssembly.assembly.Collections)
+		mAssembly.collections[id] = idx
+		mAssembly.assembly.Collections = append(mAssembly.assembly.Collections, Collection{ID: id})
+	}
+
+	return &mAssembly.assembly.Collections[idx]
+}
+
+// Returns mRun as a TestRun.
+// The counts of each assembly are calculated from its tests.
+func (mRun *messageRun) toTestRun() TestRun {
+	tRun := mRun.tRun
+	tRun.Assemblies = make([]Assembly, 0, len(mRun.assemblies))
+
+	for _, mAssembly := range mRun.assemblies {
+		tAssembly := mAssembly.assembly
+		tAssembly.TotalCount = len(mAssembly.tests)
+		tAssembly.TestGroups = GroupTests(mAssembly.tests)
+
+		for _, tc := range mAssembly.tests {
+			switch tc.Result {
+			case Pass:
+				tAssembly.PassedCount++
+			case Fail:
+				tAssembly.FailedCount++
+			case Skip:
+				tAssembly.SkippedCount++
+			case NotRun:
+				tAssembly.NotRunCount++
+			}
+		}
+
+		tRun.Assemblies = append(tRun.Assemblies, tAssembly)
+	}
+
+	tRun.ParseTimes()
+
+	return tRun
+}
+
+// Returns the name of the assembly in msg (a `test-assembly-starting` message), without its directory.
+func (msg *message) assemblyName() string {
+	if msg.AssemblyPath == "" {
+		return msg.AssemblyName
+	}
+
+	return msg.AssemblyPath[strings.LastIndexAny(msg.AssemblyPath, "/\\")+1:]
+}
+
+// Returns the test in msg (the message that contains the result of a test) as a TestCase.
+// The name and the traits of the test are taken from starting (the `test-starting` message of the test), and its
+// class and method from testCase (the `test-case-starting` message of the test case that the test belongs to).
+func (msg *message) toTestCase(starting, testCase message) TestCase {
+	tCase := NewTestCase(starting.TestDisplayName)
+	tCase.FullName = starting.fullName(testCase)
+	tCase.Time = float32(msg.ExecutionTime)
+	tCase.Duration = secondsToDuration(msg.ExecutionTime)
+	tCase.Output = msg.Output
+	tCase.Warnings = msg.Warnings
+
+	switch msg.Type {
+	case "test-passed":
+		tCase.Result = Pass
+	case "test-failed":
+		tCase.Result = Fail
+		tCase.Failure = msg.toFailure()
+	case "test-skipped":
+		tCase.Result = Skip
+		tCase.Reason = strings.TrimSpace(msg.Reason)
+	case "test-not-run":
+		tCase.Result = NotRun
+	}
+
+	for _, name := range maps.Keys(starting.Traits) {
+		for _, value := range starting.Traits[name] {
+			tCase.Traits = append(tCase.Traits, Trait{Name: name, Value: value})
+		}
+	}
+
+	return tCase
+}
+
+// Returns the fully-qualified name of the test in msg (a `test-starting` message), which belongs to the test case in
+// testCase (a `test-case-starting` message).
+// A test with a display name is qualified by its class and its method, since a display name isn't guaranteed to be
+// unique (see `test.fullName`).
+func (msg *message) fullName(testCase message) string {
+	class, method := testCase.TestClassName, testCase.TestMethodName
+
+	if class == "" || method == "" || strings.HasPrefix(msg.TestDisplayName, class+"."+method) {
+		return msg.TestDisplayName
+	}
+
+	return class + "." + method + ": " + msg.TestDisplayName
+}
+
+// Returns the outermost exception in msg (a `test-failed` message) as a Failure.
+func (msg *message) toFailure() *Failure {
+	f := &failure{}
+
+	if len(msg.ExceptionTypes) > 0 {
+		f.ExceptionType = msg.ExceptionTypes[0]
+	}
+
+	if len(msg.Messages) > 0 {
+		f.Message = msg.Messages[0]
+	}
+
+	if len(msg.StackTraces) > 0 {
+		f.StackTrace = msg.StackTraces[0]
+	}
+
+	return f.toFailure()
+}
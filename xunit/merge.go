@@ -0,0 +1,199 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package xunit contains functions for parsing files containing .NET test result(s) in xUnit's v2+ XML format, or in
+// the JSON message stream that's written by xUnit v3, and for merging test result(s) and writing them in xUnit's v2
+// XML format.
+// More information regarding this format can be found @ https://xunit.net/docs/format-xml-v2.
+package xunit
+
+// Merge returns a single TestRun which combines runs, such as the runs of a sharded test suite.
+// Assemblies with the same name are combined into a single assembly: their tests are regrouped, their counts are
+// recomputed from the tests, and their durations and environmental errors are added up, as are the counts and the
+// durations of their collections with the same name. A test that's found in more than one run (such as a test that's
+// retried) is only kept once, with the result of the last run that contains it. An assembly without any tests
+// keeps the sum of the counts of its runs. The run starts when the earliest run starts, and ends when the latest run
+// ends. The other information (such as the computer) is taken from the first run that has it.
+func Merge(runs ...TestRun) TestRun {
+	merged := TestRun{Assemblies: make([]Assembly, 0)}
+	tests := make(map[string][]TestCase)
+	seen := make(map[string]map[string]int)
+	byName := make(map[string]int)
+
+	for _, tRun := range runs {
+		merged.Computer = firstOf(merged.Computer, tRun.Computer)
+		merged.User = firstOf(merged.User, tRun.User)
+		merged.Timestamp = firstOf(merged.Timestamp, tRun.Timestamp)
+		merged.StartTimeRTF = earliest(merged.StartTimeRTF, tRun.StartTimeRTF)
+		merged.EndTimeRTF = latest(merged.EndTimeRTF, tRun.EndTimeRTF)
+
+		for _, assembly := range tRun.Assemblies {
+			idx, ok := byName[assembly.Name]
+
+			if !ok {
+				idx = len(merged.Assemblies)
+				byName[assembly.Name] = idx
+				merged.Assemblies = append(merged.Assemblies, Assembly{Name: assembly.Name})
+				seen[assembly.Name] = make(map[string]int)
+			}
+
+			merged.Assemblies[idx].add(assembly)
+
+			for _, tc := range assembly.Tests() {
+				if pos, ok := seen[assembly.Name][tc.key()]; ok {
+					tests[assembly.Name][pos] = tc
+
+					continue
+				}
+
+				seen[assembly.Name][tc.key()] = len(tests[assembly.Name])
+				tests[assembly.Name] = append(tests[assembly.Name], tc)
+			}
+		}
+	}
+
+	for idx := range merged.Assemblies {
+		mAssembly := &merged.Assemblies[idx]
+		mAssembly.TestGroups = GroupTests(tests[mAssembly.Name])
+
+		if len(tests[mAssembly.Name]) > 0 {
+			mAssembly.recount()
+		}
+	}
+
+	merged.ParseTimes()
+
+	return merged
+}
+
+// Add the counts, the duration, the collections and the environmental errors of other to a.
+// The date and the time when a started running, and its environmental information (such as its target framework), are
+// taken from other if a doesn't have them yet. The start and end time of a are widened to include the ones of other.
+func (a *Assembly) add(other Assembly) {
+	a.ErrorCount += other.ErrorCount
+	a.PassedCount += other.PassedCount
+	a.FailedCount += other.FailedCount
+	a.SkippedCount += other.SkippedCount
+	a.NotRunCount += other.NotRunCount
+	a.TotalCount += other.TotalCount
+	a.Time += other.Time
+	a.Duration += other.Duration
+	a.Errors = append(a.Errors, other.Errors...)
+
+	for _, collection := range other.Collections {
+		a.addCollection(collection)
+	}
+
+	a.ID = firstOf(a.ID, other.ID)
+	a.ConfigFile = firstOf(a.ConfigFile, other.ConfigFile)
+	a.Environment = firstOf(a.Environment, other.Environment)
+	a.TargetFramework = firstOf(a.TargetFramework, other.TargetFramework)
+	a.TestFramework = firstOf(a.TestFramework, other.TestFramework)
+	a.StartTimeRTF = earliest(a.StartTimeRTF, other.StartTimeRTF)
+	a.EndTimeRTF = latest(a.EndTimeRTF, other.EndTimeRTF)
+
+	if a.RunDate == "" && a.RunTime == "" {
+		a.RunDate = other.RunDate
+		a.RunTime = other.RunTime
+	}
+}
+
+// Add collection to a.
+// If a already has a collection with the same name, the counts and the duration of collection are added to it, and
+// its TimeRTF is cleared, since it no longer matches the duration.
+func (a *Assembly) addCollection(collection Collection) {
+	for idx := range a.Collections {
+		if c := &a.Collections[idx]; c.Name == collection.Name {
+			c.ID = firstOf(c.ID, collection.ID)
+			c.PassedCount += collection.PassedCount
+			c.FailedCount += collection.FailedCount
+			c.SkippedCount += collection.SkippedCount
+			c.NotRunCount += collection.NotRunCount
+			c.TotalCount += collection.TotalCount
+			c.Time += collection.Time
+			c.Duration += collection.Duration
+			c.TimeRTF = ""
+
+			return
+		}
+	}
+
+	a.Collections = append(a.Collections, collection)
+}
+
+// Recompute the number of tests of a, per result, from its tests.
+func (a *Assembly) recount() {
+	a.PassedCount, a.FailedCount, a.SkippedCount, a.NotRunCount, a.TotalCount = 0, 0, 0, 0, 0
+
+	for _, tc := range a.Tests() {
+		a.TotalCount++
+
+		switch tc.Result {
+		case Pass:
+			a.PassedCount++
+		case Fail:
+			a.FailedCount++
+		case Skip:
+			a.SkippedCount++
+		case NotRun:
+			a.NotRunCount++
+		}
+	}
+}
+
+// Returns v if it isn't empty, other otherwise.
+func firstOf(v, other string) string {
+	if v != "" {
+		return v
+	}
+
+	return other
+}
+
+// Returns the earliest of the RTF timestamps v and other.
+// A timestamp that can't be parsed is only returned if the other timestamp is empty.
+func earliest(v, other string) string {
+	vt, vOk := ParseTimestamp(v)
+	ot, oOk := ParseTimestamp(other)
+
+	if v == "" || (!vOk && oOk) || (vOk && oOk && ot.Before(vt)) {
+		return other
+	}
+
+	return v
+}
+
+// Returns the latest of the RTF timestamps v and other.
+// A timestamp that can't be parsed is only returned if the other timestamp is empty.
+func latest(v, other string) string {
+	vt, vOk := ParseTimestamp(v)
+	ot, oOk := ParseTimestamp(other)
+
+	if v == "" || (!vOk && oOk) || (vOk && oOk && ot.After(vt)) {
+		return other
+	}
+
+	return v
+}
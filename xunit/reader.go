@@ -23,13 +23,17 @@
 // =                OTHER DEALINGS IN THE SOFTWARE.
 // =====================================================================================================================
 
-// Package xunit contains functions for parsing XML files containing .NET test result(s) in xUnit's v2+ XML format.
+// Package xunit contains functions for parsing files containing .NET test result(s) in xUnit's v2+ XML format, or in
+// the JSON message stream that's written by xUnit v3, and for merging test result(s) and writing them in xUnit's v2
+// XML format.
 // More information regarding this format can be found @ https://xunit.net/docs/format-xml-v2.
 package xunit
 
 import (
 	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kdeconinck/camelcase"
 	"github.com/kdeconinck/maps"
@@ -44,22 +48,57 @@ type TestRun struct {
 	EndTimeRTF   string     // The time the last assembly finished running.
 	Timestamp    string     // The time the first assembly started running.
 	Assemblies   []Assembly // The assemblies that are part of this test run.
+
+	StartTime     time.Time // StartTimeRTF, parsed (see ParseTimestamp), the zero time if it isn't known.
+	EndTime       time.Time // EndTimeRTF, parsed (see ParseTimestamp), the zero time if it isn't known.
+	TimestampTime time.Time // Timestamp, parsed (see ParseTimestamp), the zero time if it isn't known.
 }
 
 // Assembly contains information about the run of a single test assembly.
 // This includes environmental information.
 type Assembly struct {
-	Name        string       // The full name of the assembly.
-	ErrorCount  int          // The total number of environmental errors experienced in the assembly.
-	PassedCount int          // The total number of test cases in the assembly which passed.
-	FailedCount int          // The total number of test cases in the assembly which failed.
-	NotRunCount int          // The total number of test cases that weren't run.
-	TotalCount  int          // The total number of test cases in the assembly.
-	RunDate     string       // The date when the test run started.
-	RunTime     string       // The time when the test run started.
-	Time        float32      // The number of seconds that the assembly took to run.
-	TimeRTF     string       // The time spent running the tests in the assembly.
-	TestGroups  []*TestGroup // All the tests of the assembly, grouped by trait.
+	ID              string             // The unique ID of the assembly within the test run.
+	Name            string             // The full name of the assembly.
+	ConfigFile      string             // The path of the configuration file that was used to run the assembly.
+	Environment     string             // The environment the assembly ran in (for example, "64-bit .NET 8.0.1 [...]").
+	TargetFramework string             // The target framework the assembly was built for (for example, "net8.0").
+	TestFramework   string             // The display name of the test framework (for example, "xUnit.net 2.5.0").
+	StartTimeRTF    string             // The time the assembly started running.
+	EndTimeRTF      string             // The time the assembly finished running.
+	ErrorCount      int                // The total number of environmental errors experienced in the assembly.
+	PassedCount     int                // The total number of test cases in the assembly which passed.
+	FailedCount     int                // The total number of test cases in the assembly which failed.
+	SkippedCount    int                // The total number of test cases in the assembly which were skipped.
+	NotRunCount     int                // The total number of test cases that weren't run.
+	TotalCount      int                // The total number of test cases in the assembly.
+	RunDate         string             // The date when the test run started.
+	RunTime         string             // The time when the test run started.
+	Time            float32            // The number of seconds that the assembly took to run.
+	TimeRTF         string             // The time spent running the tests in the assembly.
+	TestGroups      []*TestGroup       // All the tests of the assembly, grouped by trait.
+	Collections     []Collection       // The test collections of the assembly, in the order they were run.
+	Errors          []EnvironmentError // The environmental errors experienced in the assembly.
+
+	StartTime time.Time     // StartTimeRTF, parsed (see ParseTimestamp), the zero time if it isn't known.
+	EndTime   time.Time     // EndTimeRTF, parsed (see ParseTimestamp), the zero time if it isn't known.
+	RunStart  time.Time     // RunDate and RunTime, parsed (see ParseRunTime), the zero time if they aren't known.
+	Duration  time.Duration // TimeRTF (see ParseTimeSpan) if it's known, Time otherwise, without losing precision.
+}
+
+// Collection contains information about the run of a single test collection.
+// The tests in a collection run one after the other, while the collections of an assembly can run in parallel.
+// The tests of a collection are part of the groups of its assembly (see TestCase.Collection).
+type Collection struct {
+	ID           string        // The unique ID of the collection within the test run.
+	Name         string        // The name of the collection (for example, "Test collection for NS.TestClass").
+	PassedCount  int           // The total number of test cases in the collection which passed.
+	FailedCount  int           // The total number of test cases in the collection which failed.
+	SkippedCount int           // The total number of test cases in the collection which were skipped.
+	NotRunCount  int           // The total number of test cases in the collection that weren't run.
+	TotalCount   int           // The total number of test cases in the collection.
+	Time         float32       // The number of seconds that the collection took to run.
+	TimeRTF      string        // The time spent running the tests in the collection.
+	Duration     time.Duration // TimeRTF (see ParseTimeSpan) if it's known, Time otherwise, without losing precision.
 }
 
 // TestGroup is a group of tests.
@@ -71,12 +110,92 @@ type TestGroup struct {
 
 // TestCase contains information about a single test.
 type TestCase struct {
-	Name   string  // The name of the test, in human-readable format.
-	Result string  // The status of the test.
-	Time   float32 // The number of seconds that the test took to run.
+	Name       string   // The name of the test, in human-readable format.
+	FullName   string   // The fully-qualified name of the test, as it's written by the test framework.
+	Result     Result   // The status of the test.
+	Time       float32  // The number of seconds that the test took to run.
+	Reason     string   // The reason why the test was skipped, empty if the test wasn't skipped.
+	Failure    *Failure // The reason why the test failed, <nil> if the test didn't fail.
+	Output     string   // The output that was captured while running the test.
+	Warnings   []string // The warnings that were reported while running the test.
+	Groups     []string // The (nested) groups the test belongs to, in human-readable format, from outer to inner.
+	Traits     []Trait  // The traits of the test.
+	SourceFile string   // The path of the file which contains the test, empty if unknown.
+	SourceLine int      // The line (in SourceFile) on which the test is declared, 0 if unknown.
+	Collection string   // The name of the test collection the test ran in, empty if unknown.
+
+	Duration time.Duration // The time that the test took to run, without the loss of precision of Time.
+}
+
+// Trait contains a single trait name/value pair.
+type Trait struct {
+	Name  string // The name of the trait.
+	Value string // The value of the trait.
+}
+
+// An EnvironmentError is a failure that happened outside the scope of running a single test (for example, an
+// exception thrown while disposing of a fixture object).
+type EnvironmentError struct {
+	Type    string   // Where the error happened (for example, "test-collection-cleanup" or "fatal").
+	Name    string   // The name of the element (for example, the test collection) where the error happened.
+	Failure *Failure // The exception that caused the error, <nil> if it isn't known.
+}
+
+// Result is the status of a single test.
+type Result int
+
+// The different statuses of a test.
+const (
+	Unknown Result = iota // The status of the test isn't known.
+	Pass                  // The test passed.
+	Fail                  // The test failed.
+	Skip                  // The test was skipped.
+	NotRun                // The test wasn't run.
+)
+
+// ParseResult returns the Result represented by v (the value of the `result` attribute of a test).
+// The comparison is case-insensitive. If v isn't a known status, Unknown is returned.
+func ParseResult(v string) Result {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "pass":
+		return Pass
+	case "fail":
+		return Fail
+	case "skip":
+		return Skip
+	case "notrun":
+		return NotRun
+	default:
+		return Unknown
+	}
+}
+
+// String returns the name of r, as it's used in xUnit's v2+ XML format.
+func (r Result) String() string {
+	switch r {
+	case Pass:
+		return "Pass"
+	case Fail:
+		return "Fail"
+	case Skip:
+		return "Skip"
+	case NotRun:
+		return "NotRun"
+	default:
+		return "Unknown"
+	}
+}
 
-	// Internal fields.
-	groups []string
+// MarshalText returns the name of r.
+func (r Result) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Failure contains information about a test failure.
+type Failure struct {
+	ExceptionType string // The type of the exception that caused the test to fail.
+	Message       string // The message of the exception that caused the test to fail.
+	StackTrace    string // The stack trace of the exception that caused the test to fail.
 }
 
 // Load returns a TestRun constructed from the data in rdr.
@@ -92,76 +211,129 @@ func Load(rdr io.Reader) (TestRun, error) {
 
 // Read r into a TestRun.
 func readResult(r result) TestRun {
-	testRun := TestRun{
+	testRun := r.toTestRun()
+	testRun.Assemblies = make([]Assembly, 0, len(r.Assemblies))
+
+	// Loop over each assembly.
+	for _, assembly := range r.Assemblies {
+		tAssembly := assembly.toAssembly()
+		tAssembly.TestGroups = assembly.groupTests()
+
+		for _, collection := range assembly.Collections {
+			tAssembly.Collections = append(tAssembly.Collections, collection.toCollection())
+		}
+
+		for _, e := range assembly.ErrorSet.Errors {
+			tAssembly.Errors = append(tAssembly.Errors, e.toEnvironmentError())
+		}
+
+		tAssembly.ErrorCount = max(tAssembly.ErrorCount, len(tAssembly.Errors))
+
+		testRun.Assemblies = append(testRun.Assemblies, tAssembly)
+	}
+
+	return testRun
+}
+
+// Returns r as a TestRun, without any assemblies.
+func (r *result) toTestRun() TestRun {
+	tRun := TestRun{
 		Computer:     r.Computer,
 		User:         r.User,
 		StartTimeRTF: r.StartRTF,
 		EndTimeRTF:   r.FinishRTF,
 		Timestamp:    r.Timestamp,
-		Assemblies:   make([]Assembly, 0, len(r.Assemblies)),
 	}
 
-	// Loop over each assembly.
-	for _, assembly := range r.Assemblies {
-		testRun.Assemblies = append(testRun.Assemblies, Assembly{
-			Name:        assembly.name(),
-			ErrorCount:  assembly.ErrorCount,
-			PassedCount: assembly.PassedCount,
-			FailedCount: assembly.FailedCount,
-			NotRunCount: assembly.NotRunCount,
-			TotalCount:  assembly.Total,
-			RunDate:     assembly.RunDate,
-			RunTime:     assembly.RunTime,
-			TimeRTF:     assembly.TimeRTF,
-			Time:        assembly.Time,
-			TestGroups:  assembly.groupTests(),
-		})
+	tRun.ParseTimes()
+
+	return tRun
+}
+
+// Returns assembly as an Assembly, without any tests.
+// xUnit v3 might not write the run date and time, in which case they're taken from the start time of the assembly.
+func (assembly *assembly) toAssembly() Assembly {
+	tAssembly := Assembly{
+		ID:              assembly.ID,
+		Name:            assembly.name(),
+		ConfigFile:      assembly.ConfigFile,
+		Environment:     assembly.Environment,
+		TargetFramework: assembly.TargetFramework,
+		TestFramework:   assembly.TestFramework,
+		StartTimeRTF:    assembly.StartRTF,
+		EndTimeRTF:      assembly.FinishRTF,
+		ErrorCount:      assembly.ErrorCount,
+		PassedCount:     assembly.PassedCount,
+		FailedCount:     assembly.FailedCount,
+		SkippedCount:    assembly.SkippedCount,
+		NotRunCount:     assembly.NotRunCount,
+		TotalCount:      assembly.Total,
+		RunDate:         assembly.RunDate,
+		RunTime:         assembly.RunTime,
+		TimeRTF:         assembly.TimeRTF,
+		Time:            float32(assembly.Time),
+		Duration:        secondsToDuration(assembly.Time),
 	}
 
-	return testRun
+	if date, clock, ok := strings.Cut(assembly.StartRTF, "T"); ok && tAssembly.RunDate == "" {
+		tAssembly.RunDate = date
+		tAssembly.RunTime = clock
+	}
+
+	tAssembly.parseTimes()
+
+	return tAssembly
 }
 
-// Returns true if t has a display name, false otherwise.
-// When t has any space in its name, it's considered to have display name.
+// NewTestCase returns a TestCase for the test named name, with its (human-readable and fully-qualified) name and its
+// groups filled in.
+// The name is either the name of the test in xUnit's v2+ XML format (the concatenation, with a `.`, of the namespace,
+// the class, the nested class(es) separated by a `+`, and the method), or a display name.
+func NewTestCase(name string) TestCase {
+	return TestCase{Name: friendlyName(name), FullName: name, Groups: nestedGroups(name)}
+}
+
+// Returns true if name is a display name, false otherwise.
+// When name has any space in it, it's considered to be a display name.
 // This ie because by design, C# doesn't allow to have spaces in any identifier and the default name of a test is the
 // concatenation (with a `.`) of all identifiers (namespace, class, subclass(es) and methods).
-func (t *test) hasDisplayName() bool {
-	return strings.Contains(t.Name, " ")
+func hasDisplayName(name string) bool {
+	return strings.Contains(name, " ")
 }
 
-// Returns true if t is nested, false otherwise.
-// When t has any `+` character in its name and when it's NOT a display name, the test is considered nested.
-func (t *test) isNested() bool {
-	return !t.hasDisplayName() && strings.Contains(t.Name, "+")
+// Returns true if the test named name is nested, false otherwise.
+// When name has any `+` character in it and when it's NOT a display name, the test is considered nested.
+func isNested(name string) bool {
+	return !hasDisplayName(name) && strings.Contains(name, "+")
 }
 
-// Returns the friendly name of the test.
-// If t has a display name, the name is returned as is, if not, the name is split based on the `.` character.
+// Returns the friendly name of the test named name.
+// If name is a display name, it's returned as is, if not, the name is split based on the `.` character.
 // This gives us a slices of strings where each part contains a valid C# identifier. The last part would be the name of
 // the function.
 // We feed this name to the "CamelCase" package to turn it into a readable sentence.
-func (t *test) friendlyName() string {
-	if t.hasDisplayName() {
-		return t.Name
+func friendlyName(name string) string {
+	if hasDisplayName(name) {
+		return name
 	}
 
-	fnName := t.Name[strings.LastIndex(t.Name, ".")+1:]
+	fnName := name[strings.LastIndex(name, ".")+1:]
 	fnNameWords := camelcase.Split(fnName)
 
 	return words.ToSentence(fnNameWords)
 }
 
-// Returns the groups that t belongs to.
-// If t has a display name, an empty string is returned, if not, the name is split based on the `.` character.
+// Returns the groups that the test named name belongs to.
+// If name is a display name, <nil> is returned, if not, the name is split based on the `.` character.
 // This gives us a slices of strings where each part contains a valid C# identifier. The last part would be the name of
 // the function.
 // We feed this name to the "CamelCase" package to turn it into a slice of readable words.
-func (t *test) groups() []string {
-	if !t.isNested() {
-		return make([]string, 0)
+func nestedGroups(name string) []string {
+	if !isNested(name) {
+		return nil
 	}
 
-	groupName := strings.Split(t.Name, "+")
+	groupName := strings.Split(name, "+")
 
 	groupNameParts := make([]string, 0, len(groupName[1:len(groupName)-1]))
 	groupNameParts = append(groupNameParts, groupName[0][strings.LastIndex(groupName[0], ".")+1:])
@@ -179,6 +351,43 @@ func (t *test) groups() []string {
 	return groupName
 }
 
+// Returns t as a TestCase.
+func (t *test) toTestCase() TestCase {
+	tCase := NewTestCase(t.Name)
+	tCase.FullName = t.fullName()
+	tCase.Result = ParseResult(t.Result)
+	tCase.Time = float32(t.Time)
+	tCase.Duration = secondsToDuration(t.Time)
+
+	if d, ok := ParseTimeSpan(t.TimeRTF); ok {
+		tCase.Duration = d
+	}
+
+	tCase.Reason = strings.TrimSpace(t.Reason)
+	tCase.Failure = t.Failure.toFailure()
+	tCase.Output = t.Output
+	tCase.Warnings = t.WarningSet.Warnings
+	tCase.SourceFile = t.SourceFile
+	tCase.SourceLine, _ = strconv.Atoi(t.SourceLine)
+
+	for _, tTrait := range t.TraitSet.Traits {
+		tCase.Traits = append(tCase.Traits, Trait{Name: tTrait.Name, Value: tTrait.Value})
+	}
+
+	return tCase
+}
+
+// Returns the fully-qualified name of t.
+// A test with a display name is qualified by its type and its method, since a display name isn't guaranteed to be
+// unique.
+func (t *test) fullName() string {
+	if t.Type == "" || t.Method == "" || strings.HasPrefix(t.Name, t.Type+"."+t.Method) {
+		return t.Name
+	}
+
+	return t.Type + "." + t.Method + ": " + t.Name
+}
+
 // Returns the name of the assembly.
 func (assembly *assembly) name() string {
 	if strings.Contains(assembly.FullName, "/") {
@@ -188,28 +397,109 @@ func (assembly *assembly) name() string {
 	return assembly.FullName[strings.LastIndex(assembly.FullName, "\\")+1:]
 }
 
-// Returns a map of tests, grouped per trait.
+// Returns c as a Collection, without any tests.
+func (c *collection) toCollection() Collection {
+	tCollection := Collection{
+		ID:           c.ID,
+		Name:         c.Name,
+		PassedCount:  c.PassedCount,
+		FailedCount:  c.FailedCount,
+		SkippedCount: c.SkippedCount,
+		NotRunCount:  c.NotRunCount,
+		TotalCount:   c.TotalCount,
+		TimeRTF:      c.TimeRTF,
+	}
+
+	tCollection.Duration, _ = ParseSeconds(c.Time)
+	tCollection.Time = float32(tCollection.Duration.Seconds())
+
+	if d, ok := ParseTimeSpan(c.TimeRTF); ok {
+		tCollection.Duration = d
+	}
+
+	return tCollection
+}
+
+// Returns the collection of a named name, or a Collection with only its name if a doesn't have it.
+func (a Assembly) collection(name string) Collection {
+	for _, c := range a.Collections {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return Collection{Name: name}
+}
+
+// Count a test with result r in the counts of c.
+func (c *Collection) count(r Result) {
+	c.TotalCount++
+
+	switch r {
+	case Pass:
+		c.PassedCount++
+	case Fail:
+		c.FailedCount++
+	case Skip:
+		c.SkippedCount++
+	case NotRun:
+		c.NotRunCount++
+	}
+}
+
+// Returns the tests of the assembly, grouped per trait.
 func (assembly *assembly) groupTests() []*TestGroup {
-	if !assembly.hasTests() {
+	tests := make([]TestCase, 0)
+
+	for _, collection := range assembly.Collections {
+		for _, t := range collection.Tests {
+			tc := t.toTestCase()
+			tc.Collection = collection.Name
+			tests = append(tests, tc)
+		}
+	}
+
+	return GroupTests(tests)
+}
+
+// GroupTests returns tests, grouped per trait, and within each trait, grouped per (nested) group of each test.
+// A test without traits belongs to the group without a name, a test with multiple traits belongs to multiple groups.
+// The groups are sorted by name, the tests keep their order.
+func GroupTests(tests []TestCase) []*TestGroup {
+	if len(tests) == 0 {
 		return make([]*TestGroup, 0)
 	}
 
-	uniqueTraits := assembly.uniqueTraits()
+	testMap := make(map[string][]TestCase)
+
+	for _, tc := range tests {
+		if len(tc.Traits) == 0 {
+			testMap[""] = append(testMap[""], tc)
+		}
+
+		for _, tTrait := range tc.Traits {
+			traitName := tTrait.friendlyName()
+
+			testMap[traitName] = append(testMap[traitName], tc)
+		}
+	}
+
+	uniqueTraits := maps.Keys(testMap)
 	resultSet := make([]*TestGroup, 0, len(uniqueTraits))
 
 	for idx, trait := range uniqueTraits {
-		cGroup := &TestGroup{Name: trait, Tests: make([]TestCase, 0, len(assembly.testMap[trait]))}
+		cGroup := &TestGroup{Name: trait, Tests: make([]TestCase, 0, len(testMap[trait]))}
 		resultSet = append(resultSet, cGroup)
 
-		for _, tc := range assembly.testMap[trait] {
-			if len(tc.groups) == 0 {
-				cGroup.Tests = append(cGroup.Tests, TestCase{Name: tc.Name, Result: tc.Result, Time: tc.Time})
+		for _, tc := range testMap[trait] {
+			if len(tc.Groups) == 0 {
+				cGroup.Tests = append(cGroup.Tests, tc)
 			} else {
-				for idx, nn := range tc.groups {
+				for idx, nn := range tc.Groups {
 					var sGroup *TestGroup
 
 					for _, group := range cGroup.Groups {
-						if group.Name == tc.groups[idx] {
+						if group.Name == tc.Groups[idx] {
 							sGroup = group
 
 							break
@@ -221,8 +511,8 @@ func (assembly *assembly) groupTests() []*TestGroup {
 						cGroup.Groups = append(cGroup.Groups, sGroup)
 					}
 
-					if idx == len(tc.groups)-1 {
-						sGroup.Tests = append(sGroup.Tests, TestCase{Name: tc.Name, Result: tc.Result, Time: tc.Time})
+					if idx == len(tc.Groups)-1 {
+						sGroup.Tests = append(sGroup.Tests, tc)
 					}
 
 					cGroup = sGroup
@@ -236,42 +526,63 @@ func (assembly *assembly) groupTests() []*TestGroup {
 	return resultSet
 }
 
-// Returns true if the assembly has tests, false otherwise.
-func (assembly *assembly) hasTests() bool {
-	for _, collection := range assembly.Collections {
-		if len(collection.Tests) > 0 {
-			return true
+// Tests returns all the tests of the assembly, in the order they appear in its groups.
+// Since a test with multiple traits belongs to multiple groups, each test is only returned once.
+func (a Assembly) Tests() []TestCase {
+	tests := make([]TestCase, 0, a.TotalCount)
+	seen := make(map[string]bool)
+
+	var walk func(group *TestGroup)
+
+	walk = func(group *TestGroup) {
+		for _, tc := range group.Tests {
+			if key := tc.key(); !seen[key] {
+				seen[key] = true
+				tests = append(tests, tc)
+			}
+		}
+
+		for _, sGroup := range group.Groups {
+			walk(sGroup)
 		}
 	}
 
-	return false
+	for _, group := range a.TestGroups {
+		walk(group)
+	}
+
+	return tests
 }
 
-// Returns all all the unique trait(s).
-func (assembly *assembly) uniqueTraits() []string {
-	assembly.testMap = make(map[string][]TestCase)
+// Returns a key which identifies tc within its assembly.
+// A test is identified by its fully-qualified name (or its name, if that's unknown) and by its collection, since
+// tests in different classes can have the same (human-readable) name.
+func (tc TestCase) key() string {
+	name := tc.FullName
 
-	for _, collection := range assembly.Collections {
-		for _, t := range collection.Tests {
-			tCase := TestCase{Name: t.friendlyName(), groups: t.groups(), Result: t.Result, Time: t.Time}
+	if name == "" {
+		name = tc.Name
+	}
 
-			if len(t.TraitSet.Traits) == 0 {
-				assembly.testMap[""] = append(assembly.testMap[""], tCase)
-			}
+	return tc.Collection + "\x00" + name
+}
 
-			for _, tTrait := range t.TraitSet.Traits {
-				traitName := tTrait.friendlyName()
+// Returns e as an EnvironmentError.
+func (e *err) toEnvironmentError() EnvironmentError {
+	return EnvironmentError{Type: e.Type, Name: e.Name, Failure: e.Failure.toFailure()}
+}
 
-				assembly.testMap[traitName] = append(assembly.testMap[traitName], tCase)
-			}
-		}
+// Returns f as a Failure, or <nil> if f doesn't contain any information.
+func (f *failure) toFailure() *Failure {
+	if f.ExceptionType == "" && f.Message == "" && f.StackTrace == "" {
+		return nil
 	}
 
-	return maps.Keys(assembly.testMap)
+	return &Failure{ExceptionType: f.ExceptionType, Message: f.Message, StackTrace: f.StackTrace}
 }
 
 // Returns the friendly name of the trait.
-func (t *trait) friendlyName() string {
+func (t Trait) friendlyName() string {
 	var b strings.Builder
 
 	b.WriteString(t.Name)
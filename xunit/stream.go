@@ -0,0 +1,97 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package xunit contains functions for parsing files containing .NET test result(s) in xUnit's v2+ XML format, or in
+// the JSON message stream that's written by xUnit v3, and for merging test result(s) and writing them in xUnit's v2
+// XML format.
+// More information regarding this format can be found @ https://xunit.net/docs/format-xml-v2.
+package xunit
+
+import "io"
+
+// Handler contains the functions that are called by Stream, as soon as the corresponding element is decoded.
+// Each function is optional. When a function returns a NON <nil> error, Stream stops and returns that error.
+type Handler struct {
+	Run      func(run TestRun) error                         // Called for the test run, before any of its assemblies.
+	Assembly func(assembly Assembly) error                   // Called when an assembly starts, before any of its tests.
+	Test     func(assembly string, tc TestCase) error        // Called for each test, with the name of its assembly.
+	Error    func(assembly string, e EnvironmentError) error // Called for each environmental error, with its assembly.
+}
+
+// Stream reads the data in rdr and calls the functions of h as soon as the corresponding element is decoded.
+// Contrary to Load, the tests aren't grouped, and only a single test is kept in memory at any time. This makes it
+// possible to process files which are too large to fit in memory.
+// The TestRun passed to h doesn't contain any assemblies, and the Assembly passed to h doesn't contain any tests (or
+// environmental errors).
+func Stream(rdr io.Reader, h Handler) error {
+	var cAssembly, cCollection string
+
+	return decode(rdr, handler{
+		result: func(r *result) error {
+			if h.Run == nil {
+				return nil
+			}
+
+			return h.Run(r.toTestRun())
+		},
+		assembly: func(a *assembly) error {
+			cAssembly = a.name()
+
+			if h.Assembly == nil {
+				return nil
+			}
+
+			return h.Assembly(a.toAssembly())
+		},
+		collection: func(c *collection) error {
+			cCollection = c.Name
+
+			return nil
+		},
+		test: func(t *test) error {
+			if h.Test == nil {
+				return nil
+			}
+
+			tc := t.toTestCase()
+			tc.Collection = cCollection
+
+			return h.Test(cAssembly, tc)
+		},
+		errorSet: func(e *errorSet) error {
+			if h.Error == nil {
+				return nil
+			}
+
+			for _, tErr := range e.Errors {
+				if err := h.Error(cAssembly, tErr.toEnvironmentError()); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	})
+}
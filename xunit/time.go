@@ -0,0 +1,198 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package xunit contains functions for parsing files containing .NET test result(s) in xUnit's v2+ XML format, or in
+// the JSON message stream that's written by xUnit v3, and for merging test result(s) and writing them in xUnit's v2
+// XML format.
+// More information regarding this format can be found @ https://xunit.net/docs/format-xml-v2.
+package xunit
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The layouts of a timestamp, which are tried in order.
+// Values without a time zone are interpreted as UTC. When parsing, Go accepts fractional seconds even if the layout
+// doesn't contain them.
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// The layouts of xUnit's `run-date`, which depends on the culture of the machine that ran the tests.
+// Month-first is tried before day-first (as .NET's invariant culture does), so a day-first date is only recognized
+// when it can't be a month-first date.
+var dateLayouts = []string{
+	"2006-01-02",
+	"01/02/2006",
+	"1/2/2006",
+	"02/01/2006",
+	"2/1/2006",
+	"02.01.2006",
+	"2.1.2006",
+	"2006/01/02",
+}
+
+// The layouts of xUnit's `run-time`, which depends on the culture of the machine that ran the tests.
+var clockLayouts = []string{
+	"15:04:05Z07:00",
+	"15:04:05",
+	"3:04:05 PM",
+	"15:04",
+}
+
+// A .NET TimeSpan, formatted as `[-][d.]hh:mm:ss[.fffffff]`.
+var timeSpanRegex = regexp.MustCompile(`^(-)?(?:(\d+)\.)?(\d{1,2}):(\d{2}):(\d{2})(?:\.(\d{1,9}))?$`)
+
+// ParseTimes fills in the parsed time fields of tRun and its assemblies (such as StartTime) from their raw strings.
+// The duration of an assembly is taken from its TimeRTF, or from its Time if it isn't known yet. Readers of other
+// formats call it once the raw strings are filled in.
+func (tRun *TestRun) ParseTimes() {
+	tRun.StartTime, _ = ParseTimestamp(tRun.StartTimeRTF)
+	tRun.EndTime, _ = ParseTimestamp(tRun.EndTimeRTF)
+	tRun.TimestampTime, _ = ParseTimestamp(tRun.Timestamp)
+
+	for idx := range tRun.Assemblies {
+		tRun.Assemblies[idx].parseTimes()
+	}
+}
+
+// Fill in the parsed time fields of a from their raw strings.
+func (a *Assembly) parseTimes() {
+	a.StartTime, _ = ParseTimestamp(a.StartTimeRTF)
+	a.EndTime, _ = ParseTimestamp(a.EndTimeRTF)
+	a.RunStart, _ = ParseRunTime(a.RunDate, a.RunTime)
+
+	if d, ok := ParseTimeSpan(a.TimeRTF); ok {
+		a.Duration = d
+	} else if a.Duration == 0 {
+		a.Duration = secondsToDuration(float64(a.Time))
+	}
+}
+
+// ParseTimestamp returns the time in v, which is formatted as RFC 3339 (with or without fractional seconds), as a
+// date and a time separated by a `T` or a space, or as a date and a time in the format of xUnit's `run-date` and
+// `run-time` (see ParseRunTime). A value without a time zone is interpreted as UTC, and the time is returned in UTC.
+// If v isn't a valid timestamp, the zero time and false are returned.
+func ParseTimestamp(v string) (time.Time, bool) {
+	v = strings.TrimSpace(v)
+
+	if t, ok := parseFirst(timestampLayouts, v); ok {
+		return t.UTC(), true
+	}
+
+	if date, clock, ok := strings.Cut(v, " "); ok {
+		return ParseRunTime(date, clock)
+	}
+
+	return time.Time{}, false
+}
+
+// ParseRunTime returns the time in date and clock, which are the `run-date` and `run-time` of an assembly.
+// Since xUnit formats both using the culture of the machine that ran the tests, the most common formats are accepted.
+// A value without a time zone is interpreted as UTC, and the time is returned in UTC.
+// If date or clock isn't valid, the zero time and false are returned.
+func ParseRunTime(date, clock string) (time.Time, bool) {
+	d, ok := parseFirst(dateLayouts, strings.TrimSpace(date))
+
+	if !ok {
+		return time.Time{}, false
+	}
+
+	c, ok := parseFirst(clockLayouts, strings.TrimSpace(clock))
+
+	if !ok {
+		return time.Time{}, false
+	}
+
+	t := time.Date(d.Year(), d.Month(), d.Day(), c.Hour(), c.Minute(), c.Second(), c.Nanosecond(), c.Location())
+
+	return t.UTC(), true
+}
+
+// ParseTimeSpan returns the duration in v, which is a .NET TimeSpan formatted as `[-][d.]hh:mm:ss[.fffffff]` (such
+// as the `time-rtf` of an assembly).
+// If v isn't a valid TimeSpan, 0 and false are returned.
+func ParseTimeSpan(v string) (time.Duration, bool) {
+	m := timeSpanRegex.FindStringSubmatch(strings.TrimSpace(v))
+
+	if m == nil {
+		return 0, false
+	}
+
+	days, _ := strconv.Atoi(m[2])
+	hours, _ := strconv.Atoi(m[3])
+	minutes, _ := strconv.Atoi(m[4])
+	seconds, _ := strconv.Atoi(m[5])
+	nanos, _ := strconv.Atoi((m[6] + "000000000")[:9])
+
+	if minutes > 59 || seconds > 59 {
+		return 0, false
+	}
+
+	d := time.Duration(days)*24*time.Hour + time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second + time.Duration(nanos)
+
+	if m[1] != "" {
+		d = -d
+	}
+
+	return d, true
+}
+
+// ParseSeconds returns the duration in v, which is a decimal number of seconds (such as the `time` of a test).
+// If v isn't a valid number, 0 and false are returned.
+func ParseSeconds(v string) (time.Duration, bool) {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return secondsToDuration(seconds), true
+}
+
+// Returns seconds as a duration, rounded to the nearest nanosecond.
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds * float64(time.Second)))
+}
+
+// Returns the time in v, using the first layout in layouts that matches.
+func parseFirst(layouts []string, v string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,319 @@
+// =====================================================================================================================
+// = LICENSE:       Copyright (c) 2023 Kevin De Coninck
+// =
+// =                Permission is hereby granted, free of charge, to any person
+// =                obtaining a copy of this software and associated documentation
+// =                files (the "Software"), to deal in the Software without
+// =                restriction, including without limitation the rights to use,
+// =                copy, modify, merge, publish, distribute, sublicense, and/or sell
+// =                copies of the Software, and to permit persons to whom the
+// =                Software is furnished to do so, subject to the following
+// =                conditions:
+// =
+// =                The above copyright notice and this permission notice shall be
+// =                included in all copies or substantial portions of the Software.
+// =
+// =                THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// =                EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// =                OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// =                NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT
+// =                HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// =                WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// =                FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// =                OTHER DEALINGS IN THE SOFTWARE.
+// =====================================================================================================================
+
+// Package xunit contains functions for parsing files containing .NET test result(s) in xUnit's v2+ XML format, or in
+// the JSON message stream that's written by xUnit v3, and for merging test result(s) and writing them in xUnit's v2
+// XML format.
+// More information regarding this format can be found @ https://xunit.net/docs/format-xml-v2.
+package xunit
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// An xmlResult is the top-level element of a document that's written in xUnit's v2 XML format.
+type xmlResult struct {
+	XMLName    xml.Name      `xml:"assemblies"`
+	Computer   string        `xml:"computer,attr,omitempty"`
+	User       string        `xml:"user,attr,omitempty"`
+	StartRTF   string        `xml:"start-rtf,attr,omitempty"`
+	FinishRTF  string        `xml:"finish-rtf,attr,omitempty"`
+	Timestamp  string        `xml:"timestamp,attr,omitempty"`
+	Assemblies []xmlAssembly `xml:"assembly"`
+}
+
+// An xmlAssembly is a single test assembly, which is written in xUnit's v2 XML format.
+type xmlAssembly struct {
+	ID              string          `xml:"id,attr,omitempty"`
+	Name            string          `xml:"name,attr"`
+	ConfigFile      string          `xml:"config-file,attr,omitempty"`
+	Environment     string          `xml:"environment,attr,omitempty"`
+	TargetFramework string          `xml:"target-framework,attr,omitempty"`
+	TestFramework   string          `xml:"test-framework,attr,omitempty"`
+	StartRTF        string          `xml:"start-rtf,attr,omitempty"`
+	FinishRTF       string          `xml:"finish-rtf,attr,omitempty"`
+	RunDate         string          `xml:"run-date,attr,omitempty"`
+	RunTime         string          `xml:"run-time,attr,omitempty"`
+	Time            string          `xml:"time,attr"`
+	Total           int             `xml:"total,attr"`
+	Passed          int             `xml:"passed,attr"`
+	Failed          int             `xml:"failed,attr"`
+	Skipped         int             `xml:"skipped,attr"`
+	NotRun          int             `xml:"not-run,attr"`
+	Errors          int             `xml:"errors,attr"`
+	Collections     []xmlCollection `xml:"collection"`
+	ErrorSet        *xmlErrors      `xml:"errors"`
+}
+
+// An xmlErrors contains the environmental errors of an assembly, which are written in xUnit's v2 XML format.
+type xmlErrors struct {
+	Items []xmlError `xml:"error"`
+}
+
+// An xmlError is a single environmental error, which is written in xUnit's v2 XML format.
+type xmlError struct {
+	Type    string      `xml:"type,attr,omitempty"`
+	Name    string      `xml:"name,attr,omitempty"`
+	Failure *xmlFailure `xml:"failure"`
+}
+
+// An xmlCollection is a test collection, which is written in xUnit's v2 XML format.
+type xmlCollection struct {
+	ID      string    `xml:"id,attr,omitempty"`
+	Name    string    `xml:"name,attr,omitempty"`
+	Total   int       `xml:"total,attr"`
+	Passed  int       `xml:"passed,attr"`
+	Failed  int       `xml:"failed,attr"`
+	Skipped int       `xml:"skipped,attr"`
+	NotRun  int       `xml:"not-run,attr"`
+	Time    string    `xml:"time,attr"`
+	Tests   []xmlTest `xml:"test"`
+}
+
+// An xmlTest is a single test, which is written in xUnit's v2 XML format.
+type xmlTest struct {
+	Name       string       `xml:"name,attr"`
+	Type       string       `xml:"type,attr,omitempty"`
+	Method     string       `xml:"method,attr,omitempty"`
+	Time       string       `xml:"time,attr"`
+	Result     string       `xml:"result,attr"`
+	SourceFile string       `xml:"source-file,attr,omitempty"`
+	SourceLine string       `xml:"source-line,attr,omitempty"`
+	Traits     *xmlTraits   `xml:"traits"`
+	Failure    *xmlFailure  `xml:"failure"`
+	Reason     *xmlText     `xml:"reason"`
+	Output     *xmlText     `xml:"output"`
+	Warnings   *xmlWarnings `xml:"warnings"`
+}
+
+// An xmlTraits contains the traits of a test, which are written in xUnit's v2 XML format.
+type xmlTraits struct {
+	Items []trait `xml:"trait"`
+}
+
+// An xmlWarnings contains the warnings of a test, which are written in xUnit's v2 XML format.
+type xmlWarnings struct {
+	Items []xmlText `xml:"warning"`
+}
+
+// An xmlFailure is the reason why a test failed, which is written in xUnit's v2 XML format.
+type xmlFailure struct {
+	ExceptionType string   `xml:"exception-type,attr,omitempty"`
+	Message       *xmlText `xml:"message"`
+	StackTrace    *xmlText `xml:"stack-trace"`
+}
+
+// An xmlText is an element which contains text, which is written as character data.
+type xmlText struct {
+	Value string `xml:",cdata"`
+}
+
+// Write writes tRun to w in xUnit's v2 XML format.
+// The tests of each assembly are written in the test collection they ran in, and the tests without a collection in a
+// single unnamed collection. A test is written with its fully-qualified name, so that reading the document results in
+// the same (human-readable) names and groups.
+func Write(w io.Writer, tRun TestRun) error {
+	doc := xmlResult{
+		Computer:   tRun.Computer,
+		User:       tRun.User,
+		StartRTF:   tRun.StartTimeRTF,
+		FinishRTF:  tRun.EndTimeRTF,
+		Timestamp:  tRun.Timestamp,
+		Assemblies: make([]xmlAssembly, 0, len(tRun.Assemblies)),
+	}
+
+	for _, assembly := range tRun.Assemblies {
+		doc.Assemblies = append(doc.Assemblies, writeAssembly(assembly))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// Returns assembly as an xmlAssembly.
+func writeAssembly(assembly Assembly) xmlAssembly {
+	xAssembly := xmlAssembly{
+		ID:              assembly.ID,
+		Name:            assembly.Name,
+		ConfigFile:      assembly.ConfigFile,
+		Environment:     assembly.Environment,
+		TargetFramework: assembly.TargetFramework,
+		TestFramework:   assembly.TestFramework,
+		StartRTF:        assembly.StartTimeRTF,
+		FinishRTF:       assembly.EndTimeRTF,
+		RunDate:         assembly.RunDate,
+		RunTime:         assembly.RunTime,
+		Time:            formatTime(assembly.Time),
+		Total:           assembly.TotalCount,
+		Passed:          assembly.PassedCount,
+		Failed:          assembly.FailedCount,
+		Skipped:         assembly.SkippedCount,
+		NotRun:          assembly.NotRunCount,
+		Errors:          assembly.ErrorCount,
+	}
+
+	if len(assembly.Errors) > 0 {
+		xAssembly.ErrorSet = &xmlErrors{Items: make([]xmlError, 0, len(assembly.Errors))}
+
+		for _, e := range assembly.Errors {
+			xAssembly.ErrorSet.Items = append(xAssembly.ErrorSet.Items,
+				xmlError{Type: e.Type, Name: e.Name, Failure: writeFailure(e.Failure)})
+		}
+	}
+
+	names := make([]string, 0)
+	tests := make(map[string][]TestCase)
+
+	for _, tc := range assembly.Tests() {
+		if _, ok := tests[tc.Collection]; !ok {
+			names = append(names, tc.Collection)
+		}
+
+		tests[tc.Collection] = append(tests[tc.Collection], tc)
+	}
+
+	for _, name := range names {
+		xAssembly.Collections = append(xAssembly.Collections, writeCollection(assembly.collection(name), tests[name]))
+	}
+
+	return xAssembly
+}
+
+// Returns tests, which ran in collection, as an xmlCollection.
+// The counts are calculated from tests, the time is taken from collection if it's known.
+func writeCollection(collection Collection, tests []TestCase) xmlCollection {
+	xCollection := xmlCollection{ID: collection.ID, Name: collection.Name, Tests: make([]xmlTest, 0, len(tests))}
+
+	var time float32
+
+	for _, tc := range tests {
+		xCollection.Tests = append(xCollection.Tests, writeTest(tc))
+		xCollection.Total++
+		time += tc.Time
+
+		switch tc.Result {
+		case Pass:
+			xCollection.Passed++
+		case Fail:
+			xCollection.Failed++
+		case Skip:
+			xCollection.Skipped++
+		case NotRun:
+			xCollection.NotRun++
+		}
+	}
+
+	if collection.Time != 0 {
+		time = collection.Time
+	}
+
+	xCollection.Time = formatTime(time)
+
+	return xCollection
+}
+
+// Returns tc as an xmlTest.
+func writeTest(tc TestCase) xmlTest {
+	xTest := xmlTest{
+		Name:       tc.FullName,
+		Time:       formatTime(tc.Time),
+		Result:     tc.Result.String(),
+		SourceFile: tc.SourceFile,
+	}
+
+	// A test with a display name is qualified by its type and its method (see `test.fullName`).
+	if qualifier, name, ok := strings.Cut(tc.FullName, ": "); ok && !strings.Contains(qualifier, " ") {
+		if idx := strings.LastIndex(qualifier, "."); idx > 0 {
+			xTest.Name, xTest.Type, xTest.Method = name, qualifier[:idx], qualifier[idx+1:]
+		}
+	}
+
+	if tc.SourceLine > 0 {
+		xTest.SourceLine = strconv.Itoa(tc.SourceLine)
+	}
+
+	if len(tc.Traits) > 0 {
+		xTest.Traits = &xmlTraits{Items: make([]trait, 0, len(tc.Traits))}
+	}
+
+	for _, t := range tc.Traits {
+		xTest.Traits.Items = append(xTest.Traits.Items, trait{Name: t.Name, Value: t.Value})
+	}
+
+	xTest.Failure = writeFailure(tc.Failure)
+
+	if tc.Reason != "" {
+		xTest.Reason = &xmlText{Value: tc.Reason}
+	}
+
+	if tc.Output != "" {
+		xTest.Output = &xmlText{Value: tc.Output}
+	}
+
+	if len(tc.Warnings) > 0 {
+		xTest.Warnings = &xmlWarnings{Items: make([]xmlText, 0, len(tc.Warnings))}
+
+		for _, warning := range tc.Warnings {
+			xTest.Warnings.Items = append(xTest.Warnings.Items, xmlText{Value: warning})
+		}
+	}
+
+	return xTest
+}
+
+// Returns f as an xmlFailure, or <nil> if f is <nil>.
+func writeFailure(f *Failure) *xmlFailure {
+	if f == nil {
+		return nil
+	}
+
+	xFailure := &xmlFailure{ExceptionType: f.ExceptionType, Message: &xmlText{Value: f.Message}}
+
+	if f.StackTrace != "" {
+		xFailure.StackTrace = &xmlText{Value: f.StackTrace}
+	}
+
+	return xFailure
+}
+
+// Returns seconds, formatted as a decimal number.
+func formatTime(seconds float32) string {
+	return strconv.FormatFloat(float64(seconds), 'f', -1, 32)
+}
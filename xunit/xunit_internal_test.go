@@ -27,6 +27,9 @@
 package xunit
 
 import (
+	"bytes"
+	"io"
+	"runtime"
 	"strconv"
 	"strings"
 	"testing"
@@ -108,3 +111,100 @@ func benchmarkLoad(xmlData string, b *testing.B) {
 		}
 	}
 }
+
+// Benchmark: Stream a large XML file containing a .NET test result.
+func BenchmarkStream_10000Tests(b *testing.B) {
+	benchmarkStream(10000, b)
+}
+
+// Benchmark: Stream a large XML file containing a .NET test result.
+func BenchmarkStream_100000Tests(b *testing.B) {
+	benchmarkStream(100000, b)
+}
+
+// Benchmark: Load a large XML file containing a .NET test result (for comparison with streaming it).
+func BenchmarkLoad_100000Tests(b *testing.B) {
+	b.ReportAllocs()
+
+	var peak uint64
+
+	for i := 0; i < b.N; i++ {
+		tRun, _ := Load(&generator{count: 100000})
+
+		peak = max(peak, heapInUse())
+		runtime.KeepAlive(tRun)
+	}
+
+	b.ReportMetric(float64(peak), "peak-heap-B")
+}
+
+// Benchmark: Stream a large XML file containing a .NET test result.
+// Besides the regular metrics, the peak heap usage is reported, which should NOT grow with the number of tests.
+func benchmarkStream(count int, b *testing.B) {
+	b.ReportAllocs()
+
+	var peak uint64
+
+	for i := 0; i < b.N; i++ {
+		processed := 0
+
+		_ = Stream(&generator{count: count}, Handler{
+			Test: func(assembly string, tc TestCase) error {
+				if processed++; processed%10000 == 0 {
+					peak = max(peak, heapInUse())
+				}
+
+				return nil
+			},
+		})
+	}
+
+	b.ReportMetric(float64(peak), "peak-heap-B")
+}
+
+// Returns the number of bytes that are currently in use on the heap.
+func heapInUse() uint64 {
+	var stats runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+
+	return stats.HeapInuse
+}
+
+// A generator is an io.Reader which produces an XML file in xUnit's v2+ XML format, containing count tests.
+// The file is generated while it's read, so it's never kept in memory completely.
+type generator struct {
+	count int          // The number of tests to generate.
+	idx   int          // The number of tests that are generated.
+	state int          // 0: Nothing is generated, 1: The header is generated, 2: Everything is generated.
+	buf   bytes.Buffer // The data that's generated, but not read yet.
+}
+
+// Read the next chunk of the generated file into p.
+func (g *generator) Read(p []byte) (int, error) {
+	for g.buf.Len() < len(p) && g.state < 2 {
+		switch {
+		case g.state == 0:
+			g.buf.WriteString("<assemblies>\n  <assembly name=\"App.dll\">\n    <collection>\n")
+			g.state = 1
+		case g.idx < g.count:
+			g.buf.WriteString("      <test name=\"NS.TestClass+Method+Scenario.Result")
+			g.buf.WriteString(strconv.Itoa(g.idx))
+			g.buf.WriteString("\" result=\"Pass\" time=\"0.01\">\n")
+			g.buf.WriteString("        <traits><trait name=\"Category\" value=\"Unit\" /></traits>\n")
+			g.buf.WriteString("        <output>Some output that's captured while running the test.</output>\n")
+			g.buf.WriteString("      </test>\n")
+			g.idx++
+		default:
+			g.buf.WriteString("    </collection>\n  </assembly>\n</assemblies>")
+			g.state = 2
+		}
+	}
+
+	if g.buf.Len() == 0 {
+		return 0, io.EOF
+	}
+
+	return g.buf.Read(p)
+}
@@ -28,9 +28,15 @@ package xunit_test
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/kdeconinck/assert"
 	"github.com/kdeconinck/xunit"
@@ -55,11 +61,14 @@ func TestLoad(t *testing.T) {
 				"  </assembly>\n" +
 				"</assemblies>",
 			want: xunit.TestRun{
-				Computer:     "WIN11",
-				User:         "Kevin",
-				StartTimeRTF: "2000-12-01",
-				EndTimeRTF:   "2001-12-01",
-				Timestamp:    "2001-12-02",
+				Computer:      "WIN11",
+				User:          "Kevin",
+				StartTimeRTF:  "2000-12-01",
+				EndTimeRTF:    "2001-12-01",
+				Timestamp:     "2001-12-02",
+				StartTime:     time.Date(2000, 12, 1, 0, 0, 0, 0, time.UTC),
+				EndTime:       time.Date(2001, 12, 1, 0, 0, 0, 0, time.UTC),
+				TimestampTime: time.Date(2001, 12, 2, 0, 0, 0, 0, time.UTC),
 				Assemblies: []xunit.Assembly{
 					{
 						Name:        "App.dll",
@@ -70,6 +79,7 @@ func TestLoad(t *testing.T) {
 						TotalCount:  5,
 						RunDate:     "07/10/2023",
 						RunTime:     "20:53:19",
+						RunStart:    time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC),
 						TimeRTF:     "2000-12-01",
 						TestGroups:  make([]*xunit.TestGroup, 0),
 					},
@@ -79,7 +89,7 @@ func TestLoad(t *testing.T) {
 
 		{
 			xmlData: "<assemblies computer=\"WIN11\" user=\"Kevin\" timestamp=\"07/10/2023 20:53:19\" start-rtf=\"2000-12-01\" finish-rtf=\"2001-12-01\" timestamp=\"2001-12-02\">\n" +
-				"  <assembly name=\"~/parent/sub/app.dll\" errors=\"1\" failed=\"2\" passed=\"3\" not-run=\"4\" total=\"5\" run-date=\"07/10/2023\" run-time=\"20:53:19\" time-rtf=\"2000-12-01\">\n" +
+				"  <assembly name=\"~/parent/sub/app.dll\" errors=\"1\" failed=\"2\" passed=\"3\" skipped=\"6\" not-run=\"4\" total=\"5\" run-date=\"07/10/2023\" run-time=\"20:53:19\" time-rtf=\"2000-12-01\">\n" +
 				"    <collection>\n" +
 
 				// NOTE: A test which has a display name (it contains spaces, and NO `+` sign).
@@ -90,6 +100,15 @@ func TestLoad(t *testing.T) {
 				// NOTE: A NON nested test without a display name (it contains NO spaces, and NO `+` character).
 				"      <test name=\"NS1.Class.SubClass.TestClass.TestMethod\" result=\"Fail\">\n" +
 				"        <traits />\n" +
+				"        <failure exception-type=\"System.InvalidOperationException\">\n" +
+				"          <message>Operation is not valid.</message>\n" +
+				"          <stack-trace>   at NS1.Class.SubClass.TestClass.TestMethod()</stack-trace>\n" +
+				"        </failure>\n" +
+				"      </test>\n" +
+
+				// NOTE: A skipped test, with a reason.
+				"      <test name=\"NS1.Class.SubClass.TestClass.SkippedMethod\" result=\"Skip\">\n" +
+				"        <reason><![CDATA[Not implemented yet.]]></reason>\n" +
 				"      </test>\n" +
 
 				// NOTE: A nested test (it contains the `+` character).
@@ -120,33 +139,51 @@ func TestLoad(t *testing.T) {
 				"  </assembly>\n" +
 				"</assemblies>",
 			want: xunit.TestRun{
-				Computer:     "WIN11",
-				User:         "Kevin",
-				StartTimeRTF: "2000-12-01",
-				EndTimeRTF:   "2001-12-01",
-				Timestamp:    "2001-12-02",
+				Computer:      "WIN11",
+				User:          "Kevin",
+				StartTimeRTF:  "2000-12-01",
+				EndTimeRTF:    "2001-12-01",
+				Timestamp:     "2001-12-02",
+				StartTime:     time.Date(2000, 12, 1, 0, 0, 0, 0, time.UTC),
+				EndTime:       time.Date(2001, 12, 1, 0, 0, 0, 0, time.UTC),
+				TimestampTime: time.Date(2001, 12, 2, 0, 0, 0, 0, time.UTC),
 				Assemblies: []xunit.Assembly{
 					{
-						Name:        "app.dll",
-						ErrorCount:  1,
-						PassedCount: 3,
-						FailedCount: 2,
-						NotRunCount: 4,
-						TotalCount:  5,
-						RunDate:     "07/10/2023",
-						RunTime:     "20:53:19",
-						TimeRTF:     "2000-12-01",
+						Name:         "app.dll",
+						ErrorCount:   1,
+						PassedCount:  3,
+						FailedCount:  2,
+						SkippedCount: 6,
+						NotRunCount:  4,
+						TotalCount:   5,
+						RunDate:      "07/10/2023",
+						RunTime:      "20:53:19",
+						RunStart:     time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC),
+						TimeRTF:      "2000-12-01",
 						TestGroups: []*xunit.TestGroup{
 							{
 								Name: "",
 								Tests: []xunit.TestCase{
 									{
-										Name:   "A test with a display name.",
-										Result: "Pass",
+										Name:     "A test with a display name.",
+										FullName: "A test with a display name.",
+										Result:   xunit.Pass,
 									},
 									{
-										Name:   "Test method",
-										Result: "Fail",
+										Name:     "Test method",
+										FullName: "NS1.Class.SubClass.TestClass.TestMethod",
+										Result:   xunit.Fail,
+										Failure: &xunit.Failure{
+											ExceptionType: "System.InvalidOperationException",
+											Message:       "Operation is not valid.",
+											StackTrace:    "   at NS1.Class.SubClass.TestClass.TestMethod()",
+										},
+									},
+									{
+										Name:     "Skipped method",
+										FullName: "NS1.Class.SubClass.TestClass.SkippedMethod",
+										Result:   xunit.Skip,
+										Reason:   "Not implemented yet.",
 									},
 								},
 								Groups: []*xunit.TestGroup{
@@ -166,8 +203,10 @@ func TestLoad(t *testing.T) {
 																Name: "Sub scenario",
 																Tests: []xunit.TestCase{
 																	{
-																		Name:   "Result",
-																		Result: "Pass",
+																		Name:     "Result",
+																		FullName: "NS1.Class.SubClass.TestClass+Method+Scenario+SubScenario.Result",
+																		Result:   xunit.Pass,
+																		Groups:   []string{"Test class", "Method", "Scenario", "Sub scenario"},
 																	},
 																},
 															},
@@ -181,8 +220,10 @@ func TestLoad(t *testing.T) {
 																Name: "Sub scenario",
 																Tests: []xunit.TestCase{
 																	{
-																		Name:   "Result",
-																		Result: "Pass",
+																		Name:     "Result",
+																		FullName: "NS1.Class.SubClass.TestClass+Method+Scenario2+SubScenario.Result",
+																		Result:   xunit.Pass,
+																		Groups:   []string{"Test class", "Method", "Scenario2", "Sub scenario"},
 																	},
 																},
 															},
@@ -198,12 +239,16 @@ func TestLoad(t *testing.T) {
 								Name: "Category - Unit",
 								Tests: []xunit.TestCase{
 									{
-										Name:   "A test with a display name (with a trait).",
-										Result: "Pass",
+										Name:     "A test with a display name (with a trait).",
+										FullName: "A test with a display name (with a trait).",
+										Result:   xunit.Pass,
+										Traits:   []xunit.Trait{{Name: "Category", Value: "Unit"}},
 									},
 									{
-										Name:   "A test with a display name (with multiple traits).",
-										Result: "Pass",
+										Name:     "A test with a display name (with multiple traits).",
+										FullName: "A test with a display name (with multiple traits).",
+										Result:   xunit.Pass,
+										Traits:   []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Timing", Value: "Slow"}},
 									},
 								},
 							},
@@ -211,12 +256,73 @@ func TestLoad(t *testing.T) {
 								Name: "Timing - Slow",
 								Tests: []xunit.TestCase{
 									{
-										Name:   "A test with a display name (with multiple traits).",
-										Result: "Pass",
+										Name:     "A test with a display name (with multiple traits).",
+										FullName: "A test with a display name (with multiple traits).",
+										Result:   xunit.Pass,
+										Traits:   []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Timing", Value: "Slow"}},
+									},
+								},
+							},
+						},
+						Collections: []xunit.Collection{{}},
+					},
+				},
+			},
+		},
+		{
+			xmlData: "<assemblies schema-version=\"3\" id=\"a1\" start-rtf=\"2024-05-01T10:00:00.0000000+00:00\">\n" +
+				"  <assembly name=\"/src/App.dll\" id=\"b2\" start-rtf=\"2024-05-01T10:00:00.0000000+00:00\" finish-rtf=\"2024-05-01T10:00:01.0000000+00:00\" test-framework=\"xUnit.net v3\" passed=\"1\" total=\"1\" time=\"1\"" +
+				" config-file=\"/src/xunit.runner.json\" environment=\"64-bit .NET 8.0.1 [collection-per-class, parallel (16 threads)]\" target-framework=\"net8.0\">\n" +
+				"    <collection id=\"c3\" name=\"Test collection for NS.TestClass\" total=\"2\" passed=\"2\" time=\"0.5\">\n" +
+				"      <test id=\"d4\" name=\"NS.TestClass.ReturnsTrue\" result=\"Pass\" time=\"0.5\" start-rtf=\"2024-05-01T10:00:00.1000000+00:00\" finish-rtf=\"2024-05-01T10:00:00.6000000+00:00\" source-file=\"/src/TestClass.cs\" source-line=\"12\" />\n" +
+				"      <test id=\"e5\" name=\"Adds numbers\" type=\"NS.TestClass\" method=\"Add\" result=\"Pass\" />\n" +
+				"    </collection>\n" +
+				"  </assembly>\n" +
+				"</assemblies>",
+			want: xunit.TestRun{
+				StartTimeRTF: "2024-05-01T10:00:00.0000000+00:00",
+				StartTime:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				Assemblies: []xunit.Assembly{
+					{
+						ID:              "b2",
+						Name:            "App.dll",
+						ConfigFile:      "/src/xunit.runner.json",
+						Environment:     "64-bit .NET 8.0.1 [collection-per-class, parallel (16 threads)]",
+						TargetFramework: "net8.0",
+						TestFramework:   "xUnit.net v3",
+						StartTimeRTF:    "2024-05-01T10:00:00.0000000+00:00",
+						EndTimeRTF:      "2024-05-01T10:00:01.0000000+00:00",
+						PassedCount:     1,
+						TotalCount:      1,
+						RunDate:         "2024-05-01",
+						RunTime:         "10:00:00.0000000+00:00",
+						Time:            1,
+						StartTime:       time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+						EndTime:         time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC),
+						RunStart:        time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+						Duration:        time.Second,
+						TestGroups: []*xunit.TestGroup{
+							{
+								Name: "",
+								Tests: []xunit.TestCase{
+									{
+										Name: "Returns true", FullName: "NS.TestClass.ReturnsTrue", Result: xunit.Pass, Time: 0.5,
+										Duration: 500 * time.Millisecond, SourceFile: "/src/TestClass.cs", SourceLine: 12,
+										Collection: "Test collection for NS.TestClass",
+									},
+									{
+										Name: "Adds numbers", FullName: "NS.TestClass.Add: Adds numbers", Result: xunit.Pass,
+										Collection: "Test collection for NS.TestClass",
 									},
 								},
 							},
 						},
+						Collections: []xunit.Collection{
+							{
+								ID: "c3", Name: "Test collection for NS.TestClass", PassedCount: 2, TotalCount: 2, Time: 0.5,
+								Duration: 500 * time.Millisecond,
+							},
+						},
 					},
 				},
 			},
@@ -269,3 +375,978 @@ func TestLoad(t *testing.T) {
 			fmtXml(tc.xmlData), fmtValue(tc.want), fmtValue(got))
 	}
 }
+
+// UT: Parse the status of a test.
+func TestParseResult(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		v    string
+		want xunit.Result
+	}{
+		{v: "Pass", want: xunit.Pass},
+		{v: "Fail", want: xunit.Fail},
+		{v: "Skip", want: xunit.Skip},
+		{v: "NotRun", want: xunit.NotRun},
+		{v: "notrun", want: xunit.NotRun},
+		{v: " pass ", want: xunit.Pass},
+		{v: "", want: xunit.Unknown},
+		{v: "Passed", want: xunit.Unknown},
+	} {
+		// ACT.
+		got := xunit.ParseResult(tc.v)
+
+		// ASSERT.
+		assert.Equal(t, got, tc.want, "", "\n\n"+
+			"UT Name:    Parse the status of a test.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %v\033[0m\n"+
+			"\033[31mActual:     %v\033[0m\n\n", tc.v, tc.want, got)
+	}
+}
+
+// UT: Parse a timestamp.
+func TestParseTimestamp(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		v      string
+		want   time.Time
+		wantOk bool
+	}{
+		{v: "2024-05-01T10:00:00Z", want: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), wantOk: true},
+		{v: "2024-05-01T10:00:01.2345678+02:00", want: time.Date(2024, 5, 1, 8, 0, 1, 234567800, time.UTC), wantOk: true},
+		{v: "2024-05-01T10:00:00.500", want: time.Date(2024, 5, 1, 10, 0, 0, 500000000, time.UTC), wantOk: true},
+		{v: "2024-05-01 10:00:00", want: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), wantOk: true},
+		{v: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), wantOk: true},
+		{v: "07/10/2023 20:53:19", want: time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC), wantOk: true},
+		{v: "", want: time.Time{}, wantOk: false},
+		{v: "yesterday", want: time.Time{}, wantOk: false},
+	} {
+		// ACT.
+		got, ok := xunit.ParseTimestamp(tc.v)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got time.Time, want time.Time) bool {
+			return got.Equal(want) && ok == tc.wantOk
+		}, "", "\n\n"+
+			"UT Name:    Parse a timestamp.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %v, %t\033[0m\n"+
+			"\033[31mActual:     %v, %t\033[0m\n\n", tc.v, tc.want, tc.wantOk, got, ok)
+	}
+}
+
+// UT: Parse the `run-date` and `run-time` of an assembly.
+func TestParseRunTime(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		date, clock string
+		want        time.Time
+		wantOk      bool
+	}{
+		{date: "2024-05-01", clock: "10:00:00", want: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), wantOk: true},
+		{date: "07/10/2023", clock: "20:53:19", want: time.Date(2023, 7, 10, 20, 53, 19, 0, time.UTC), wantOk: true},
+		{date: "5/1/2024", clock: "8:53:19 PM", want: time.Date(2024, 5, 1, 20, 53, 19, 0, time.UTC), wantOk: true},
+		{date: "25/12/2023", clock: "09:15", want: time.Date(2023, 12, 25, 9, 15, 0, 0, time.UTC), wantOk: true},
+		{date: "25.12.2023", clock: "09:15:00", want: time.Date(2023, 12, 25, 9, 15, 0, 0, time.UTC), wantOk: true},
+		{
+			date: "2024-05-01", clock: "00:30:00.5000000+02:00",
+			want: time.Date(2024, 4, 30, 22, 30, 0, 500000000, time.UTC), wantOk: true,
+		},
+		{date: "", clock: "10:00:00", want: time.Time{}, wantOk: false},
+		{date: "2024-05-01", clock: "", want: time.Time{}, wantOk: false},
+	} {
+		// ACT.
+		got, ok := xunit.ParseRunTime(tc.date, tc.clock)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got time.Time, want time.Time) bool {
+			return got.Equal(want) && ok == tc.wantOk
+		}, "", "\n\n"+
+			"UT Name:    Parse the `run-date` and `run-time` of an assembly.\n"+
+			"Input:      %q, %q\n"+
+			"\033[32mExpected:   %v, %t\033[0m\n"+
+			"\033[31mActual:     %v, %t\033[0m\n\n", tc.date, tc.clock, tc.want, tc.wantOk, got, ok)
+	}
+}
+
+// UT: Parse a .NET TimeSpan.
+func TestParseTimeSpan(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		v      string
+		want   time.Duration
+		wantOk bool
+	}{
+		{v: "00:00:01.2345678", want: 1234567800 * time.Nanosecond, wantOk: true},
+		{v: "01:02:03", want: time.Hour + 2*time.Minute + 3*time.Second, wantOk: true},
+		{v: "1.00:00:00.5", want: 24*time.Hour + 500*time.Millisecond, wantOk: true},
+		{v: "-00:00:02", want: -2 * time.Second, wantOk: true},
+		{v: "00:60:00", want: 0, wantOk: false},
+		{v: "2000-12-01", want: 0, wantOk: false},
+		{v: "", want: 0, wantOk: false},
+	} {
+		// ACT.
+		got, ok := xunit.ParseTimeSpan(tc.v)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got time.Duration, want time.Duration) bool {
+			return got == want && ok == tc.wantOk
+		}, "", "\n\n"+
+			"UT Name:    Parse a .NET TimeSpan.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %v, %t\033[0m\n"+
+			"\033[31mActual:     %v, %t\033[0m\n\n", tc.v, tc.want, tc.wantOk, got, ok)
+	}
+}
+
+// UT: Parse a decimal number of seconds.
+func TestParseSeconds(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		v      string
+		want   time.Duration
+		wantOk bool
+	}{
+		{v: "0.25", want: 250 * time.Millisecond, wantOk: true},
+		{v: " 3 ", want: 3 * time.Second, wantOk: true},
+		{v: "86400.0000001", want: 24*time.Hour + 100*time.Nanosecond, wantOk: true},
+		{v: "slow", want: 0, wantOk: false},
+		{v: "", want: 0, wantOk: false},
+	} {
+		// ACT.
+		got, ok := xunit.ParseSeconds(tc.v)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got time.Duration, want time.Duration) bool {
+			return got == want && ok == tc.wantOk
+		}, "", "\n\n"+
+			"UT Name:    Parse a decimal number of seconds.\n"+
+			"Input:      %q\n"+
+			"\033[32mExpected:   %v, %t\033[0m\n"+
+			"\033[31mActual:     %v, %t\033[0m\n\n", tc.v, tc.want, tc.wantOk, got, ok)
+	}
+}
+
+// UT: Stream an XML file containing a .NET test result.
+func TestStream(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies computer=\"WIN11\" user=\"Kevin\">\n" +
+		"  <assembly name=\"C:\\Parent\\App.dll\" failed=\"1\" passed=\"1\" total=\"2\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.TestClass+Method.Result\" result=\"Pass\" time=\"0.5\">\n" +
+		"        <traits>\n" +
+		"          <trait name=\"Category\" value=\"Unit\" />\n" +
+		"        </traits>\n" +
+		"      </test>\n" +
+		"      <unknown><test name=\"Ignored\" /></unknown>\n" +
+		"    </collection>\n" +
+		"    <errors>\n" +
+		"      <error type=\"test-collection-cleanup\" name=\"Test collection for NS.TestClass\">\n" +
+		"        <failure exception-type=\"System.ObjectDisposedException\"><message>Cannot access a disposed object.</message></failure>\n" +
+		"      </error>\n" +
+		"    </errors>\n" +
+		"  </assembly>\n" +
+		"  <assembly name=\"/parent/Other.dll\">\n" +
+		"    <collection>\n" +
+		"      <test name=\"NS.TestClass.TestMethod\" result=\"Fail\" />\n" +
+		"    </collection>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	got := make([]string, 0)
+
+	// ACT.
+	err := xunit.Stream(strings.NewReader(xmlData), xunit.Handler{
+		Run: func(run xunit.TestRun) error {
+			got = append(got, "Run: "+run.Computer+" / "+run.User)
+
+			return nil
+		},
+		Assembly: func(assembly xunit.Assembly) error {
+			got = append(got, "Assembly: "+assembly.Name)
+
+			return nil
+		},
+		Test: func(assembly string, tc xunit.TestCase) error {
+			got = append(got, "Test: "+assembly+" / "+strings.Join(tc.Groups, " / ")+" / "+tc.Name+" / "+tc.Result.String())
+
+			return nil
+		},
+		Error: func(assembly string, e xunit.EnvironmentError) error {
+			got = append(got, "Error: "+assembly+" / "+e.Type+" / "+e.Name+" / "+e.Failure.Message)
+
+			return nil
+		},
+	})
+
+	// ASSERT.
+	want := []string{
+		"Run: WIN11 / Kevin",
+		"Assembly: App.dll",
+		"Test: App.dll / Test class / Method / Result / Pass",
+		"Error: App.dll / test-collection-cleanup / Test collection for NS.TestClass / Cannot access a disposed object.",
+		"Assembly: Other.dll",
+		"Test: Other.dll /  / Test method / Fail",
+	}
+
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Stream an XML file containing a .NET test result in xUnit's v2+ XML format.\n"+
+		"XML Input:  %s\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", xmlData, err)
+
+	assert.EqualS(t, got, want, "", "\n\n"+
+		"UT Name:    Stream an XML file containing a .NET test result in xUnit's v2+ XML format.\n"+
+		"XML Input:  %s\n"+
+		"\033[32mExpected:   %v\033[0m\n"+
+		"\033[31mActual:     %v\033[0m\n\n", xmlData, want, got)
+}
+
+// UT: Stop streaming an XML file containing a .NET test result when a handler fails.
+func TestStream_HandlerError(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies><assembly><collection><test /><test /></collection></assembly></assemblies>"
+	wantErr := errors.New("handler failed")
+	count := 0
+
+	// ACT.
+	err := xunit.Stream(strings.NewReader(xmlData), xunit.Handler{
+		Test: func(assembly string, tc xunit.TestCase) error {
+			count++
+
+			return wantErr
+		},
+	})
+
+	// ASSERT.
+	assert.Equal(t, err, wantErr, "", "\n\n"+
+		"UT Name:    Stop streaming when a handler fails.\n"+
+		"\033[32mExpected:   Error, %v\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", wantErr, err)
+
+	assert.Equal(t, count, 1, "", "\n\n"+
+		"UT Name:    Stop streaming when a handler fails.\n"+
+		"\033[32mExpected:   1 test\033[0m\n"+
+		"\033[31mActual:     %v test(s)\033[0m\n\n", count)
+}
+
+// UT: Load invalid data, which should result in a typed error.
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name    string
+		rdr     io.Reader
+		isValid func(err error) bool
+		wantMsg string
+	}{
+		{
+			name:    "Load data that can't be read.",
+			rdr:     io.MultiReader(strings.NewReader("<assemblies>"), iotest.ErrReader(errors.New("connection reset"))),
+			isValid: func(err error) bool { var e *xunit.ReadError; return errors.As(err, &e) },
+			wantMsg: "failed to read the data: connection reset",
+		},
+		{
+			name:    "Load an empty file.",
+			rdr:     strings.NewReader(""),
+			isValid: func(err error) bool { var e *xunit.RootElementError; return errors.As(err, &e) && e.Name == "" },
+			wantMsg: "no root element found, expected <assemblies>",
+		},
+		{
+			name:    "Load a truncated file.",
+			rdr:     strings.NewReader("<assemblies>\n  <assembly name=\"App.dll\">\n    <collection>\n      <test"),
+			isValid: func(err error) bool { var e *xunit.SyntaxError; return errors.As(err, &e) && e.Line == 4 },
+			wantMsg: "malformed XML at line 4, column 12: unexpected EOF",
+		},
+		{
+			name:    "Load a file with an invalid attribute value.",
+			rdr:     strings.NewReader("<assemblies>\n  <assembly total=\"many\">\n  </assembly>\n</assemblies>"),
+			isValid: func(err error) bool { var e *xunit.SyntaxError; return errors.As(err, &e) && e.Line == 2 },
+			wantMsg: "malformed XML at line 2, column 26: strconv.ParseInt: parsing \"many\": invalid syntax",
+		},
+		{
+			name:    "Load a file with an unexpected root element.",
+			rdr:     strings.NewReader("<TestRun></TestRun>"),
+			isValid: func(err error) bool { var e *xunit.RootElementError; return errors.As(err, &e) && e.Name == "TestRun" },
+			wantMsg: "unexpected root element <TestRun>, expected <assemblies>",
+		},
+		{
+			name:    "Load a file with an unsupported schema version.",
+			rdr:     strings.NewReader("<assemblies schema-version=\"99\"></assemblies>"),
+			isValid: func(err error) bool { var e *xunit.SchemaVersionError; return errors.As(err, &e) },
+			wantMsg: "unsupported schema version '99'",
+		},
+	} {
+		// ACT.
+		_, err := xunit.Load(tc.rdr)
+
+		// ASSERT.
+		assert.Equal(t, err != nil && tc.isValid(err), true, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"\033[32mExpected:   A typed error\033[0m\n"+
+			"\033[31mActual:     Error, %#v\033[0m\n\n", tc.name, err)
+
+		assert.Equal(t, err.Error(), tc.wantMsg, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.wantMsg, err.Error())
+	}
+}
+
+// UT: Create a test case, based on the name of a test.
+func TestNewTestCase(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name string
+		want xunit.TestCase
+	}{
+		{
+			name: "A test with a display name.",
+			want: xunit.TestCase{Name: "A test with a display name.", FullName: "A test with a display name."},
+		},
+		{
+			name: "NS.TestClass.TestMethod",
+			want: xunit.TestCase{Name: "Test method", FullName: "NS.TestClass.TestMethod"},
+		},
+		{
+			name: "NS.TestClass+NestedClass.TestMethod",
+			want: xunit.TestCase{Name: "Test method", FullName: "NS.TestClass+NestedClass.TestMethod", Groups: []string{"Test class", "Nested class"}},
+		},
+	} {
+		// ACT.
+		got := xunit.NewTestCase(tc.name)
+
+		// ASSERT.
+		assert.EqualFn(t, got, tc.want, func(got xunit.TestCase, want xunit.TestCase) bool {
+			return reflect.DeepEqual(got, want)
+		}, "", "\n\n"+
+			"UT Name:    Create a test case, based on the name of a test.\n"+
+			"Input:      %s\n"+
+			"\033[32mExpected:   %+v\033[0m\n"+
+			"\033[31mActual:     %+v\033[0m\n\n", tc.name, tc.want, got)
+	}
+}
+
+// UT: Get all the tests of an assembly.
+func TestAssembly_Tests(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	tests := []xunit.TestCase{
+		{
+			Name: "Test 1", FullName: "NS.Tests.Test1",
+			Traits: []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Owner", Value: "Kevin"}},
+		},
+		{
+			Name: "Test 2", FullName: "NS.TestClass+NestedClass.Test2", Groups: []string{"Test class", "Nested class"},
+			Traits: []xunit.Trait{{Name: "Category", Value: "Unit"}},
+		},
+		{Name: "Test 3", FullName: "NS.Tests.Test3"},
+		{Name: "Test 3", FullName: "NS.TestClass+Test3", Groups: []string{"Test class"}},
+
+		// NOTE: Tests with the same (human-readable) name, in different classes.
+		{Name: "Works", FullName: "NS1.ClassA.Works", Result: xunit.Pass},
+		{Name: "Works", FullName: "NS2.ClassB.Works", Result: xunit.Fail},
+	}
+
+	assembly := xunit.Assembly{TotalCount: len(tests), TestGroups: xunit.GroupTests(tests)}
+	want := []xunit.TestCase{tests[2], tests[4], tests[5], tests[3], tests[0], tests[1]}
+
+	// ACT.
+	got := assembly.Tests()
+
+	// ASSERT.
+	assert.EqualFn(t, got, want, func(got []xunit.TestCase, want []xunit.TestCase) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Get all the tests of an assembly.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Load a message stream containing a .NET test result in xUnit's v3 JSON format.
+func TestLoadJSON(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	jsonData := `{"$type":"test-assembly-starting","AssemblyUniqueID":"a1","AssemblyName":"App","AssemblyPath":"/src/App.dll","StartTime":"2024-05-01T10:00:00.000+00:00",` +
+		`"ConfigFilePath":"/src/xunit.runner.json","TargetFramework":".NETCoreApp,Version=v8.0","TestEnvironment":"64-bit .NET 8.0.1","TestFrameworkDisplayName":"xUnit.net v3 0.1.1"}` + "\n" +
+		`{"$type":"test-collection-starting","AssemblyUniqueID":"a1","TestCollectionUniqueID":"c1","TestCollectionDisplayName":"Test collection for NS.TestClass"}` + "\n" +
+		`{"$type":"test-case-starting","AssemblyUniqueID":"a1","TestCaseUniqueID":"tc1","TestClassName":"NS.TestClass+Method","TestMethodName":"ReturnsTrue"}` + "\n" +
+		`{"$type":"test-starting","AssemblyUniqueID":"a1","TestCaseUniqueID":"tc1","TestUniqueID":"t1","TestDisplayName":"NS.TestClass+Method.ReturnsTrue","Traits":{"Owner":["Kevin"],"Category":["Unit","Fast"]}}` + "\n" +
+		`{"$type":"test-starting","AssemblyUniqueID":"a1","TestUniqueID":"t2","TestDisplayName":"NS.TestClass.ThrowsAnException"}` + "\n" +
+		`{"$type":"test-passed","AssemblyUniqueID":"a1","TestCollectionUniqueID":"c1","TestUniqueID":"t1","ExecutionTime":0.25,"Output":"Some output.","Warnings":["Deprecated API."]}` + "\n" +
+		`{"$type":"test-failed","AssemblyUniqueID":"a1","TestCollectionUniqueID":"c1","TestUniqueID":"t2","ExecutionTime":0.5,"ExceptionTypes":["System.InvalidOperationException","System.Exception"],"Messages":["Operation is not valid.","Inner."],"StackTraces":["at NS.TestClass.ThrowsAnException()",""]}` + "\n" +
+		`{"$type":"test-collection-finished","AssemblyUniqueID":"a1","TestCollectionUniqueID":"c1","ExecutionTime":0.75}` + "\n" +
+		"\n" +
+		`{"$type":"test-case-starting","AssemblyUniqueID":"a1","TestCaseUniqueID":"tc3","TestClassName":"NS.TestClass","TestMethodName":"Skipped"}` + "\n" +
+		`{"$type":"test-starting","AssemblyUniqueID":"a1","TestCaseUniqueID":"tc3","TestUniqueID":"t3","TestDisplayName":"Is skipped"}` + "\n" +
+		`{"$type":"test-skipped","AssemblyUniqueID":"a1","TestUniqueID":"t3","Reason":" Not implemented yet. "}` + "\n" +
+		`{"$type":"test-case-finished","AssemblyUniqueID":"a1","TestCaseUniqueID":"tc3"}` + "\n" +
+		`{"$type":"test-class-cleanup-failure","AssemblyUniqueID":"a1","ExceptionTypes":["System.IO.IOException"],"Messages":["The file is in use."],"StackTraces":[""]}` + "\n" +
+		`{"$type":"test-assembly-finished","AssemblyUniqueID":"a1","ExecutionTime":1.5,"FinishTime":"2024-05-01T10:00:01.500+00:00"}`
+
+	wantTest := xunit.TestCase{
+		Name:       "Returns true",
+		FullName:   "NS.TestClass+Method.ReturnsTrue",
+		Result:     xunit.Pass,
+		Time:       0.25,
+		Duration:   250 * time.Millisecond,
+		Output:     "Some output.",
+		Warnings:   []string{"Deprecated API."},
+		Groups:     []string{"Test class", "Method"},
+		Collection: "Test collection for NS.TestClass",
+		Traits:     []xunit.Trait{{Name: "Category", Value: "Unit"}, {Name: "Category", Value: "Fast"}, {Name: "Owner", Value: "Kevin"}},
+	}
+
+	want := xunit.TestRun{
+		StartTimeRTF: "2024-05-01T10:00:00.000+00:00",
+		EndTimeRTF:   "2024-05-01T10:00:01.500+00:00",
+		StartTime:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:      time.Date(2024, 5, 1, 10, 0, 1, 500000000, time.UTC),
+		Assemblies: []xunit.Assembly{
+			{
+				ID:              "a1",
+				Name:            "App.dll",
+				ConfigFile:      "/src/xunit.runner.json",
+				Environment:     "64-bit .NET 8.0.1",
+				TargetFramework: ".NETCoreApp,Version=v8.0",
+				TestFramework:   "xUnit.net v3 0.1.1",
+				StartTimeRTF:    "2024-05-01T10:00:00.000+00:00",
+				EndTimeRTF:      "2024-05-01T10:00:01.500+00:00",
+				ErrorCount:      1,
+				PassedCount:     1,
+				FailedCount:     1,
+				SkippedCount:    1,
+				TotalCount:      3,
+				RunDate:         "2024-05-01",
+				RunTime:         "10:00:00.000+00:00",
+				Time:            1.5,
+				StartTime:       time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				EndTime:         time.Date(2024, 5, 1, 10, 0, 1, 500000000, time.UTC),
+				RunStart:        time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				Duration:        1500 * time.Millisecond,
+				TestGroups: []*xunit.TestGroup{
+					{
+						Name: "",
+						Tests: []xunit.TestCase{
+							{
+								Name:     "Throws an exception",
+								FullName: "NS.TestClass.ThrowsAnException",
+								Result:   xunit.Fail,
+								Time:     0.5,
+								Duration: 500 * time.Millisecond,
+								Failure: &xunit.Failure{
+									ExceptionType: "System.InvalidOperationException",
+									Message:       "Operation is not valid.",
+									StackTrace:    "at NS.TestClass.ThrowsAnException()",
+								},
+								Collection: "Test collection for NS.TestClass",
+							},
+							{
+								Name:     "Is skipped",
+								FullName: "NS.TestClass.Skipped: Is skipped",
+								Result:   xunit.Skip,
+								Reason:   "Not implemented yet.",
+							},
+						},
+					},
+					{
+						Name:   "Category - Fast",
+						Tests:  []xunit.TestCase{},
+						Groups: []*xunit.TestGroup{{Name: "Test class", Groups: []*xunit.TestGroup{{Name: "Method", Tests: []xunit.TestCase{wantTest}}}}},
+					},
+					{
+						Name:   "Category - Unit",
+						Tests:  []xunit.TestCase{},
+						Groups: []*xunit.TestGroup{{Name: "Test class", Groups: []*xunit.TestGroup{{Name: "Method", Tests: []xunit.TestCase{wantTest}}}}},
+					},
+					{
+						Name:   "Owner - Kevin",
+						Tests:  []xunit.TestCase{},
+						Groups: []*xunit.TestGroup{{Name: "Test class", Groups: []*xunit.TestGroup{{Name: "Method", Tests: []xunit.TestCase{wantTest}}}}},
+					},
+				},
+				Collections: []xunit.Collection{
+					{
+						ID: "c1", Name: "Test collection for NS.TestClass", PassedCount: 1, FailedCount: 1, TotalCount: 2,
+						Time: 0.75, Duration: 750 * time.Millisecond,
+					},
+				},
+				Errors: []xunit.EnvironmentError{
+					{
+						Type:    "test-class-cleanup",
+						Failure: &xunit.Failure{ExceptionType: "System.IO.IOException", Message: "The file is in use."},
+					},
+				},
+			},
+		},
+	}
+
+	// ACT.
+	got, err := xunit.LoadJSON(strings.NewReader(jsonData))
+
+	// ASSERT.
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Load a message stream containing a .NET test result in xUnit's v3 JSON format.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+
+	assert.EqualFn(t, got, want, func(got xunit.TestRun, want xunit.TestRun) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Load a message stream containing a .NET test result in xUnit's v3 JSON format.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Load a message stream in xUnit's v3 JSON format, which contains tests with the same display name.
+func TestLoadJSON_SameNames(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	jsonData := `{"$type":"test-assembly-starting","AssemblyUniqueID":"a1","AssemblyPath":"/src/App.dll"}` + "\n" +
+		`{"$type":"test-case-starting","AssemblyUniqueID":"a1","TestCaseUniqueID":"tc1","TestClassName":"NS.First","TestMethodName":"Add"}` + "\n" +
+		`{"$type":"test-case-starting","AssemblyUniqueID":"a1","TestCaseUniqueID":"tc2","TestClassName":"NS.Second","TestMethodName":"Add"}` + "\n" +
+		`{"$type":"test-starting","AssemblyUniqueID":"a1","TestCaseUniqueID":"tc1","TestUniqueID":"t1","TestDisplayName":"Adds numbers"}` + "\n" +
+		`{"$type":"test-starting","AssemblyUniqueID":"a1","TestCaseUniqueID":"tc2","TestUniqueID":"t2","TestDisplayName":"Adds numbers"}` + "\n" +
+		`{"$type":"test-passed","AssemblyUniqueID":"a1","TestUniqueID":"t1"}` + "\n" +
+		`{"$type":"test-failed","AssemblyUniqueID":"a1","TestUniqueID":"t2"}`
+
+	// ACT.
+	tRun, err := xunit.LoadJSON(strings.NewReader(jsonData))
+
+	// ASSERT.
+	assert.Nil(t, err, "", "\n\n"+
+		"UT Name:    Load a message stream in xUnit's v3 JSON format, which contains tests with the same display name.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", err)
+
+	var failed int
+
+	tests := tRun.Assemblies[0].Tests()
+
+	for _, tc := range tests {
+		if tc.Result == xunit.Fail {
+			failed++
+		}
+	}
+
+	assert.Equal(t, [2]int{len(tests), failed}, [2]int{2, 1}, "", "\n\n"+
+		"UT Name:    Load a message stream in xUnit's v3 JSON format, which contains tests with the same display name.\n"+
+		"\033[32mExpected:   Tests: 2, Failed: 1\033[0m\n"+
+		"\033[31mActual:     Tests: %v, Failed: %v\033[0m\n\n", len(tests), failed)
+}
+
+// UT: Load an invalid message stream containing a .NET test result in xUnit's v3 JSON format.
+func TestLoadJSON_Errors(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	for _, tc := range []struct {
+		name    string
+		rdr     io.Reader
+		isValid func(err error) bool
+		wantMsg string
+	}{
+		{
+			name:    "Load data that can't be read.",
+			rdr:     io.MultiReader(strings.NewReader("{}\n"), iotest.ErrReader(errors.New("connection reset"))),
+			isValid: func(err error) bool { var e *xunit.ReadError; return errors.As(err, &e) },
+			wantMsg: "failed to read the data: connection reset",
+		},
+		{
+			name:    "Load a truncated message.",
+			rdr:     strings.NewReader("{\"$type\":\"test-starting\"}\n{\"$type\":\"test-passed\","),
+			isValid: func(err error) bool { var e *xunit.SyntaxError; return errors.As(err, &e) && e.Line == 2 },
+			wantMsg: "malformed JSON at line 2, column 23: unexpected end of JSON input",
+		},
+		{
+			name:    "Load a message with an invalid value.",
+			rdr:     strings.NewReader("{\"$type\":\"test-passed\",\"ExecutionTime\":\"slow\"}"),
+			isValid: func(err error) bool { var e *xunit.SyntaxError; return errors.As(err, &e) && e.Line == 1 },
+			wantMsg: "malformed JSON at line 1, column 45: json: cannot unmarshal string into Go struct field message.ExecutionTime of type float64",
+		},
+	} {
+		// ACT.
+		_, err := xunit.LoadJSON(tc.rdr)
+
+		// ASSERT.
+		assert.Equal(t, err != nil && tc.isValid(err), true, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"\033[32mExpected:   A typed error\033[0m\n"+
+			"\033[31mActual:     Error, %#v\033[0m\n\n", tc.name, err)
+
+		assert.Equal(t, err.Error(), tc.wantMsg, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.wantMsg, err.Error())
+	}
+}
+
+// UT: Merge multiple test runs into a single test run.
+func TestMerge(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	shard1 := xunit.TestRun{
+		Computer:     "AGENT1",
+		StartTimeRTF: "2024-05-01T10:00:05.0000000+00:00",
+		EndTimeRTF:   "2024-05-01T10:00:10.0000000+00:00",
+		Assemblies: []xunit.Assembly{
+			{
+				Name: "App.dll", PassedCount: 1, TotalCount: 1, Time: 1, RunDate: "2024-05-01", RunTime: "10:00:05",
+				TestGroups: xunit.GroupTests([]xunit.TestCase{{Name: "Test 1", FullName: "NS.TestClass.Test1", Result: xunit.Pass}}),
+				Collections: []xunit.Collection{
+					{Name: "Collection", PassedCount: 1, TotalCount: 1, Time: 1, TimeRTF: "00:00:01", Duration: time.Second},
+				},
+			},
+		},
+	}
+
+	shard2 := xunit.TestRun{
+		Computer:     "AGENT2",
+		StartTimeRTF: "2024-05-01T11:00:00.0000000+02:00",
+		EndTimeRTF:   "2024-05-01T10:00:20.0000000+00:00",
+		Assemblies: []xunit.Assembly{
+			{
+				Name: "App.dll", FailedCount: 1, ErrorCount: 1, TotalCount: 1, Time: 2,
+				TestGroups: xunit.GroupTests([]xunit.TestCase{{Name: "Test 2", FullName: "NS.TestClass.Test2", Result: xunit.Fail}}),
+				Collections: []xunit.Collection{
+					{Name: "Collection", FailedCount: 1, TotalCount: 1, Time: 2, Duration: 2 * time.Second},
+				},
+			},
+			{Name: "Other.dll", TestGroups: []*xunit.TestGroup{}},
+		},
+	}
+
+	want := xunit.TestRun{
+		Computer:     "AGENT1",
+		StartTimeRTF: "2024-05-01T11:00:00.0000000+02:00",
+		EndTimeRTF:   "2024-05-01T10:00:20.0000000+00:00",
+		StartTime:    time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:      time.Date(2024, 5, 1, 10, 0, 20, 0, time.UTC),
+		Assemblies: []xunit.Assembly{
+			{
+				Name: "App.dll", PassedCount: 1, FailedCount: 1, ErrorCount: 1, TotalCount: 2, Time: 3,
+				RunDate: "2024-05-01", RunTime: "10:00:05",
+				RunStart: time.Date(2024, 5, 1, 10, 0, 5, 0, time.UTC), Duration: 3 * time.Second,
+				TestGroups: xunit.GroupTests([]xunit.TestCase{
+					{Name: "Test 1", FullName: "NS.TestClass.Test1", Result: xunit.Pass},
+					{Name: "Test 2", FullName: "NS.TestClass.Test2", Result: xunit.Fail},
+				}),
+				Collections: []xunit.Collection{
+					{Name: "Collection", PassedCount: 1, FailedCount: 1, TotalCount: 2, Time: 3, Duration: 3 * time.Second},
+				},
+			},
+			{Name: "Other.dll", TestGroups: xunit.GroupTests(nil)},
+		},
+	}
+
+	// ACT.
+	got := xunit.Merge(shard1, shard2)
+
+	// ASSERT.
+	assert.EqualFn(t, got, want, func(got xunit.TestRun, want xunit.TestRun) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Merge multiple test runs into a single test run.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Merge multiple test runs, which contain tests with the same name in different classes.
+func TestMerge_SameNames(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	shard1 := xunit.TestRun{
+		Assemblies: []xunit.Assembly{
+			{
+				Name: "App.dll", PassedCount: 1, TotalCount: 1,
+				TestGroups: xunit.GroupTests([]xunit.TestCase{xunit.NewTestCase("NS1.ClassA.Works")}),
+			},
+		},
+	}
+
+	failing := xunit.NewTestCase("NS2.ClassB.Works")
+	failing.Result = xunit.Fail
+
+	shard2 := xunit.TestRun{
+		Assemblies: []xunit.Assembly{
+			{Name: "App.dll", FailedCount: 1, TotalCount: 1, TestGroups: xunit.GroupTests([]xunit.TestCase{failing})},
+		},
+	}
+
+	// ACT.
+	got := xunit.Merge(shard1, shard2).Assemblies[0]
+
+	// ASSERT.
+	assert.Equal(t, [2]int{got.TotalCount, got.FailedCount}, [2]int{2, 1}, "", "\n\n"+
+		"UT Name:    Merge multiple test runs, which contain tests with the same name in different classes.\n"+
+		"\033[32mExpected:   Total: 2, Failed: 1\033[0m\n"+
+		"\033[31mActual:     Total: %v, Failed: %v\033[0m\n\n", got.TotalCount, got.FailedCount)
+}
+
+// UT: Merge multiple test runs, which contain the same test (such as a test that's retried).
+func TestMerge_Retried(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	failing := xunit.NewTestCase("NS.TestClass.Flaky")
+	failing.Result = xunit.Fail
+
+	passing := xunit.NewTestCase("NS.TestClass.Flaky")
+	passing.Result = xunit.Pass
+
+	shard1 := xunit.TestRun{
+		StartTimeRTF: "2024-05-01T10:00:05",
+		EndTimeRTF:   "2024-05-01T10:00:10",
+		Assemblies: []xunit.Assembly{
+			{
+				Name: "App.dll", FailedCount: 1, TotalCount: 1,
+				TestGroups: xunit.GroupTests([]xunit.TestCase{failing}),
+			},
+		},
+	}
+
+	shard2 := xunit.TestRun{
+		StartTimeRTF: "2024-05-01T10:00:00",
+		EndTimeRTF:   "2024-05-01T10:00:20",
+		Assemblies: []xunit.Assembly{
+			{
+				Name: "App.dll", PassedCount: 1, TotalCount: 1,
+				TestGroups: xunit.GroupTests([]xunit.TestCase{passing}),
+			},
+		},
+	}
+
+	want := xunit.TestRun{
+		StartTimeRTF: "2024-05-01T10:00:00",
+		EndTimeRTF:   "2024-05-01T10:00:20",
+		StartTime:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:      time.Date(2024, 5, 1, 10, 0, 20, 0, time.UTC),
+		Assemblies: []xunit.Assembly{
+			{
+				Name: "App.dll", PassedCount: 1, TotalCount: 1,
+				TestGroups: xunit.GroupTests([]xunit.TestCase{passing}),
+			},
+		},
+	}
+
+	// ACT.
+	got := xunit.Merge(shard1, shard2)
+
+	// ASSERT.
+	assert.EqualFn(t, got, want, func(got xunit.TestRun, want xunit.TestRun) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Merge multiple test runs, which contain the same test (such as a test that's retried).\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// UT: Write a test result in xUnit's v2 XML format, and read it again.
+func TestWrite(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	xmlData := "<assemblies computer=\"WIN11\" user=\"Kevin\" start-rtf=\"2024-05-01T10:00:00.0000000+00:00\">\n" +
+		"  <assembly name=\"App.dll\" run-date=\"2024-05-01\" run-time=\"10:00:00\" time=\"1.5\" total=\"4\" passed=\"1\" failed=\"1\" skipped=\"1\" not-run=\"1\" errors=\"1\"" +
+		" id=\"a1\" config-file=\"/src/xunit.runner.json\" environment=\"64-bit .NET 8.0.1\" target-framework=\"net8.0\" test-framework=\"xUnit.net 2.5.0\"" +
+		" start-rtf=\"2024-05-01T10:00:00.0000000+00:00\" finish-rtf=\"2024-05-01T10:00:01.5000000+00:00\">\n" +
+		"    <collection id=\"c1\" name=\"Test collection for NS.TestClass\" total=\"4\" passed=\"1\" failed=\"1\" skipped=\"1\" not-run=\"1\" time=\"1.25\">\n" +
+		"      <test name=\"NS.TestClass+Method.ReturnsTrue\" result=\"Pass\" time=\"0.25\" source-file=\"/src/TestClass.cs\" source-line=\"12\">\n" +
+		"        <traits><trait name=\"Category\" value=\"Unit\" /></traits>\n" +
+		"        <output>Some output.</output>\n" +
+		"        <warnings><warning>Deprecated API.</warning><warning>Slow &amp; flaky.</warning></warnings>\n" +
+		"      </test>\n" +
+		"      <test name=\"Throws an exception\" type=\"NS.TestClass\" method=\"Throws\" result=\"Fail\" time=\"1\">\n" +
+		"        <failure exception-type=\"System.Exception\"><message>Boom &amp; bang.</message><stack-trace>   at NS.TestClass.Throws()</stack-trace></failure>\n" +
+		"      </test>\n" +
+		"      <test name=\"NS.TestClass.IsSkipped\" result=\"Skip\"><reason>Not implemented yet.</reason></test>\n" +
+		"      <test name=\"NS.TestClass.IsNotRun\" result=\"NotRun\" />\n" +
+		"    </collection>\n" +
+		"    <errors>\n" +
+		"      <error type=\"fatal\"><failure exception-type=\"System.Exception\"><message>Crashed.</message></failure></error>\n" +
+		"    </errors>\n" +
+		"  </assembly>\n" +
+		"</assemblies>"
+
+	want, _ := xunit.Load(strings.NewReader(xmlData))
+
+	// ACT.
+	var b strings.Builder
+
+	err := xunit.Write(&b, want)
+	got, loadErr := xunit.Load(strings.NewReader(b.String()))
+
+	// ASSERT.
+	assert.Nil(t, errors.Join(err, loadErr), "", "\n\n"+
+		"UT Name:    Write a test result in xUnit's v2 XML format, and read it again.\n"+
+		"\033[32mExpected:   Error, <nil>\033[0m\n"+
+		"\033[31mActual:     Error, %v\033[0m\n\n", errors.Join(err, loadErr))
+
+	assert.EqualFn(t, got, want, func(got xunit.TestRun, want xunit.TestRun) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Write a test result in xUnit's v2 XML format, and read it again.\n"+
+		"XML Output: %s\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", b.String(), want, got)
+}
+
+// UT: Filter the tests of a test result.
+func TestFilter_Apply(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	unit := xunit.Trait{Name: "Category", Value: "Unit"}
+	integration := xunit.Trait{Name: "Category", Value: "Integration"}
+
+	tRun := xunit.TestRun{
+		Computer: "WIN11",
+		Assemblies: []xunit.Assembly{
+			{
+				Name: `C:\src\App.dll`, PassedCount: 2, FailedCount: 1, SkippedCount: 1, TotalCount: 4,
+				TestGroups: xunit.GroupTests([]xunit.TestCase{
+					{Name: "Returns true", FullName: "NS.Calc+Add.ReturnsTrue", Groups: []string{"Calc", "Add"}, Result: xunit.Pass, Traits: []xunit.Trait{unit}},
+					{Name: "Throws", FullName: "NS.Calc.Throws", Groups: []string{"Calc"}, Result: xunit.Fail, Traits: []xunit.Trait{unit, integration}},
+					{Name: "Is skipped", FullName: "NS.Calc.IsSkipped", Groups: []string{"Calc"}, Result: xunit.Skip},
+					{Name: "Connects", FullName: "NS.Db.Connects", Result: xunit.Pass, Traits: []xunit.Trait{integration}},
+				}),
+			},
+			{
+				Name: "Other.dll", PassedCount: 1, SkippedCount: 1, TotalCount: 2,
+				TestGroups: xunit.GroupTests([]xunit.TestCase{
+					{Name: "Works", FullName: "NS.Other.Works", Result: xunit.Pass},
+					{Name: "Works", FullName: "NS.Another.Works", Result: xunit.Skip},
+				}),
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter xunit.Filter
+		want   string
+	}{
+		{
+			name:   "Filter without any criteria.",
+			filter: xunit.Filter{},
+			want: `C:\src\App.dll (2/1/1/4): Is skipped, Connects, Throws, Returns true; ` +
+				"Other.dll (1/0/1/2): Works, Works",
+		},
+		{
+			name:   "Filter on result.",
+			filter: xunit.Filter{Results: []xunit.Result{xunit.Fail, xunit.Skip}},
+			want:   `C:\src\App.dll (0/1/1/2): Is skipped, Throws; Other.dll (0/0/1/1): Works`,
+		},
+		{
+			name:   "Filter on name, with tests which have the same name in different classes.",
+			filter: xunit.Filter{Name: regexp.MustCompile(`Works$`)},
+			want:   "Other.dll (1/0/1/2): Works, Works",
+		},
+		{
+			name:   "Filter on trait.",
+			filter: xunit.Filter{Traits: []xunit.Trait{integration}},
+			want:   `C:\src\App.dll (1/1/0/2): Connects, Throws [Category - Integration]`,
+		},
+		{
+			name:   "Filter on assembly, without its path.",
+			filter: xunit.Filter{Assemblies: []string{"app.dll"}},
+			want:   `C:\src\App.dll (2/1/1/4): Is skipped, Connects, Throws, Returns true`,
+		},
+		{
+			name:   "Filter on name.",
+			filter: xunit.Filter{Name: regexp.MustCompile(`^NS\.Calc\.`), ExcludeName: regexp.MustCompile("(?i)skipped")},
+			want:   `C:\src\App.dll (0/1/0/1): Throws`,
+		},
+	} {
+		// ACT.
+		got := tc.filter.Apply(tRun)
+
+		// ASSERT.
+		gotDesc := describe(got)
+
+		assert.Equal(t, gotDesc, tc.want, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"\033[32mExpected:   %s\033[0m\n"+
+			"\033[31mActual:     %s\033[0m\n\n", tc.name, tc.want, gotDesc)
+
+		assert.Equal(t, got.Computer, tRun.Computer, "", "\n\n"+
+			"UT Name:    %s\n"+
+			"\033[32mExpected:   Computer %s\033[0m\n"+
+			"\033[31mActual:     Computer %s\033[0m\n\n", tc.name, tRun.Computer, got.Computer)
+	}
+}
+
+// UT: Filter the tests of a test result, and recompute the collections of its assemblies.
+func TestFilter_Apply_Collections(t *testing.T) {
+	t.Parallel() // Enable "parallel" execution.
+
+	// ARRANGE.
+	tRun := xunit.TestRun{
+		Assemblies: []xunit.Assembly{
+			{
+				Name: "App.dll", PassedCount: 2, FailedCount: 1, TotalCount: 3, Time: 6,
+				TestGroups: xunit.GroupTests([]xunit.TestCase{
+					{Name: "Adds", FullName: "NS.Calc.Adds", Result: xunit.Pass, Time: 1, Duration: time.Second, Collection: "Calc"},
+					{Name: "Throws", FullName: "NS.Calc.Throws", Result: xunit.Fail, Time: 2, Duration: 2 * time.Second, Collection: "Calc"},
+					{Name: "Connects", FullName: "NS.Db.Connects", Result: xunit.Pass, Time: 3, Duration: 3 * time.Second, Collection: "Db"},
+				}),
+				Collections: []xunit.Collection{
+					{Name: "Calc", PassedCount: 1, FailedCount: 1, TotalCount: 2, Time: 3, TimeRTF: "00:00:03", Duration: 3 * time.Second},
+					{Name: "Db", PassedCount: 1, TotalCount: 1, Time: 3, TimeRTF: "00:00:03", Duration: 3 * time.Second},
+				},
+			},
+		},
+	}
+
+	want := []xunit.Collection{{Name: "Calc", FailedCount: 1, TotalCount: 1, Time: 2, Duration: 2 * time.Second}}
+
+	// ACT.
+	got := xunit.Filter{Results: []xunit.Result{xunit.Fail}}.Apply(tRun).Assemblies[0].Collections
+
+	// ASSERT.
+	assert.EqualFn(t, got, want, func(got []xunit.Collection, want []xunit.Collection) bool {
+		return reflect.DeepEqual(got, want)
+	}, "", "\n\n"+
+		"UT Name:    Filter the tests of a test result, and recompute the collections of its assemblies.\n"+
+		"\033[32mExpected:   %+v\033[0m\n"+
+		"\033[31mActual:     %+v\033[0m\n\n", want, got)
+}
+
+// Returns a description of the assemblies in tRun, with their counts (passed/failed/skipped/total) and the names of
+// their tests. When an assembly has a single group which is named after a trait, the name of the group is included.
+func describe(tRun xunit.TestRun) string {
+	parts := make([]string, 0, len(tRun.Assemblies))
+
+	for _, assembly := range tRun.Assemblies {
+		names := make([]string, 0)
+
+		for _, tc := range assembly.Tests() {
+			names = append(names, tc.Name)
+		}
+
+		desc := fmt.Sprintf("%s (%v/%v/%v/%v): %s", assembly.Name, assembly.PassedCount, assembly.FailedCount,
+			assembly.SkippedCount, assembly.TotalCount, strings.Join(names, ", "))
+
+		if len(assembly.TestGroups) == 1 && assembly.TestGroups[0].Name != "" {
+			desc += " [" + assembly.TestGroups[0].Name + "]"
+		}
+
+		parts = append(parts, desc)
+	}
+
+	return strings.Join(parts, "; ")
+}